@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/configmap/informer"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+)
+
+func main() {
+	ctx := signals.NewContext()
+	env := adapter.ConstructEnvOrDie(dispatcher.NewEnvConfig)
+
+	logger := env.GetLogger()
+	defer logger.Sync()
+	ctx = logging.WithLogger(ctx, logger)
+
+	kubeClient, err := kubernetes.NewForConfig(injection.ParseAndGetRESTConfigOrDie())
+	if err != nil {
+		logger.Fatalw("Failed to create the Kubernetes client", zap.Error(err))
+	}
+	watcher := informer.NewInformedWatcher(kubeClient, env.GetNamespace())
+
+	d, err := dispatcher.NewDispatcher(ctx, env, watcher)
+	if err != nil {
+		logger.Fatalw("Failed to create dispatcher", zap.Error(err))
+	}
+
+	if err := d.Start(ctx); err != nil {
+		logger.Fatalw("Failed to dispatch events", zap.Error(err))
+	}
+}
@@ -19,20 +19,36 @@ package main
 import (
 	"log"
 
+	"go.uber.org/zap"
 	adapter "knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/logging"
+	k8sruntime "knative.dev/pkg/observability/runtime/k8s"
 	"knative.dev/pkg/signals"
 
 	"knative.dev/eventing-redis/pkg/sink/receiver"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 )
 
 func main() {
 	ctx := signals.NewContext()
 	env := adapter.ConstructEnvOrDie(receiver.NewEnvConfig)
-	r := receiver.NewReceiver(ctx, env)
 
-	c, err := cloudevents.NewDefaultClient()
+	logger := env.GetLogger()
+	defer logger.Sync()
+	ctx = logging.WithLogger(ctx, logger)
+
+	// Exports the metrics configured by K_OBSERVABILITY_CONFIG.
+	adapter.NewObservabilityConfiguratorFromEnvironment(env).
+		SetupObservabilityOrDie(ctx, "redis-stream-sink", logger, k8sruntime.NewProfilingServer(logger.Named("pprof")))
+
+	r, err := receiver.NewReceiver(ctx, env)
+	if err != nil {
+		logger.Fatalw("Failed to create receiver", zap.Error(err))
+	}
+
+	c, err := cloudevents.NewClientHTTP(cehttp.WithMiddleware(receiver.RetryAfter))
 	if err != nil {
 		log.Fatal("Failed to create client, ", err)
 	}
@@ -0,0 +1,33 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
+	"knative.dev/eventing-redis/pkg/webhook"
+)
+
+var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+	sinksv1alpha1.SchemeGroupVersion.WithKind("RedisStreamSink"): &sinksv1alpha1.RedisStreamSink{},
+}
+
+func main() {
+	webhook.Main("redis.sinks.knative.dev", types)
+}
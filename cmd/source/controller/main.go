@@ -19,9 +19,10 @@ package main
 import (
 	"knative.dev/pkg/injection/sharedmain"
 
+	"knative.dev/eventing-redis/pkg/source/reconciler/pubsubsource"
 	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource"
 )
 
 func main() {
-	sharedmain.Main("redis-controller", streamsource.NewController)
+	sharedmain.Main("redis-controller", streamsource.NewController, pubsubsource.NewController)
 }
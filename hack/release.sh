@@ -26,6 +26,8 @@ declare -A COMPONENTS
 COMPONENTS=(
   ["redis-source.yaml"]="config/source"
   ["redis-sink.yaml"]="config/sink"
+  ["redis-channel.yaml"]="config/channel"
+  ["redis-broker.yaml"]="config/broker"
 )
 readonly COMPONENTS
 
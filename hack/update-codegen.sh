@@ -41,6 +41,11 @@ ${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
   "sinks:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
 
+${CODEGEN_PKG}/generate-groups.sh "deepcopy,client,informer,lister" \
+  knative.dev/eventing-redis/pkg/channel/client knative.dev/eventing-redis/pkg/channel/apis \
+  "messaging:v1alpha1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
+
 group "Knative Codegen"
 
 # Knative Injection
@@ -54,6 +59,11 @@ ${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
   "sinks:v1alpha1" \
   --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
 
+${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
+  knative.dev/eventing-redis/pkg/channel/client knative.dev/eventing-redis/pkg/channel/apis \
+  "messaging:v1alpha1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate.go.txt
+
 group "Update deps post-codegen"
 
 # Make sure our dependencies are up-to-date
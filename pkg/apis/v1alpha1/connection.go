@@ -20,22 +20,63 @@ import corev1 "k8s.io/api/core/v1"
 // RedisConnection defines the address and options to connect to a Redis instance
 // +k8s:deepcopy-gen=true
 type RedisConnection struct {
-	// Address is the Redis TCP address
+	// Address is the Redis TCP address. When Sentinel is set, the host of
+	// the address is ignored but its credentials and database still apply.
+	// When Cluster is set, the host of the address is used as a seed node.
 	Address string `json:"address"`
 
 	// Options are the connection options
 	// +optional
 	Options *RedisConnectionOptions `json:"dialOptions,omitempty"`
+
+	// Sentinel enables Redis Sentinel mode. The address of the current
+	// master is discovered through the sentinels and connections are
+	// re-established when a failover happens.
+	// +optional
+	Sentinel *RedisSentinel `json:"sentinel,omitempty"`
+
+	// Cluster enables Redis Cluster mode. The cluster topology is discovered
+	// from the nodes and each command is sent to the node owning the slot of
+	// its key, following redirections while the cluster is resharded.
+	// +optional
+	Cluster *RedisCluster `json:"cluster,omitempty"`
+}
+
+// RedisCluster defines how to discover the Redis Cluster topology
+// +k8s:deepcopy-gen=true
+type RedisCluster struct {
+	// Addresses are the host:port TCP addresses of additional cluster nodes
+	// used to discover the topology, besides the host of Address.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// RedisSentinel defines how to discover the Redis master through Redis Sentinel
+// +k8s:deepcopy-gen=true
+type RedisSentinel struct {
+	// MasterName is the name of the master monitored by the sentinels
+	MasterName string `json:"masterName"`
+
+	// Addresses are the host:port TCP addresses of the sentinels
+	Addresses []string `json:"addresses"`
+
+	// Password is the Kubernetes secret containing the password used to
+	// authenticate against the sentinels.
+	// +optional
+	Password *RedisSecretValueFromSource `json:"password,omitempty"`
 }
 
 // RedisConnection defines the desired state of the RedisStreamSource.
 // +k8s:deepcopy-gen=true
 type RedisConnectionOptions struct {
-	// Password to use for connecting to Redis
+	// Password to use for connecting to Redis. It references a Secret in
+	// the namespace of the resource, the field path being the key holding
+	// the password within the secret. The key defaults to "password".
 	// +optional
 	Password corev1.ObjectReference `json:"password,omitempty"`
 
-	// UseTLS indicates whether to use TLS or not
+	// UseTLS indicates whether to use TLS or not. TLS is also used when
+	// the scheme of the address is rediss.
 	// +optional
 	UseTLS bool `json:"useTLS,omitempty"`
 
@@ -52,6 +93,8 @@ type RedisConnectionOptions struct {
 	Key RedisSecretValueFromSource `json:"key,omitempty"`
 
 	// CACert is the Kubernetes secret containing the server CA cert.
+	// Without it, the server certificate is verified against the system
+	// roots.
 	// +optional
 	CACert RedisSecretValueFromSource `json:"caCert,omitempty"`
 }
@@ -62,3 +105,23 @@ type RedisSecretValueFromSource struct {
 	// The Secret key to select from.
 	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
+
+// DefaultPasswordKey is the key of the password in the secret referenced by
+// the dial options, when the reference has no field path.
+const DefaultPasswordKey = "password"
+
+// PasswordSecretKeyRef returns the secret key holding the password, or nil
+// when no password secret is referenced.
+func (o *RedisConnectionOptions) PasswordSecretKeyRef() *corev1.SecretKeySelector {
+	if o == nil || o.Password.Name == "" {
+		return nil
+	}
+	key := o.Password.FieldPath
+	if key == "" {
+		key = DefaultPasswordKey
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: o.Password.Name},
+		Key:                  key,
+	}
+}
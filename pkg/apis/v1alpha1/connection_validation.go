@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	redisParse "github.com/go-redis/redis/v8"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Validate checks that the address parses, that the sentinel and cluster
+// modes are complete and exclusive, and that the secrets are well referenced.
+func (c *RedisConnection) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if c.Address == "" {
+		// The nodes are discovered through the sentinels or the cluster
+		// addresses, which leave nothing else to configure.
+		if c.Sentinel == nil && (c.Cluster == nil || len(c.Cluster.Addresses) == 0) {
+			errs = errs.Also(apis.ErrMissingField("address"))
+		}
+	} else if _, err := redisParse.ParseURL(c.Address); err != nil {
+		// The address is not part of the error since it may hold a password.
+		errs = errs.Also(&apis.FieldError{
+			Message: "invalid address",
+			Paths:   []string{"address"},
+			Details: "expected a redis://, rediss:// or unix:// URL",
+		})
+	}
+
+	if c.Sentinel != nil && c.Cluster != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("sentinel", "cluster"))
+	}
+	errs = errs.Also(c.Options.Validate(ctx).ViaField("dialOptions"))
+	errs = errs.Also(c.Sentinel.Validate(ctx).ViaField("sentinel"))
+	return errs.Also(c.Cluster.Validate(ctx).ViaField("cluster"))
+}
+
+// Validate checks that the password secret is named, and that the client
+// certificate and key are set together.
+func (o *RedisConnectionOptions) Validate(ctx context.Context) *apis.FieldError {
+	if o == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if o.Password.Name == "" && o.Password != (corev1.ObjectReference{}) {
+		errs = errs.Also(apis.ErrMissingField("password.name"))
+	}
+	if (o.Cert.SecretKeyRef == nil) != (o.Key.SecretKeyRef == nil) {
+		errs = errs.Also(apis.ErrGeneric("expected both the client certificate and key, got one", "cert", "key"))
+	}
+	errs = errs.Also(o.Cert.Validate(ctx).ViaField("cert"))
+	errs = errs.Also(o.Key.Validate(ctx).ViaField("key"))
+	return errs.Also(o.CACert.Validate(ctx).ViaField("caCert"))
+}
+
+// Validate checks that the master name and the sentinel addresses are set.
+func (s *RedisSentinel) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if s.MasterName == "" {
+		errs = errs.Also(apis.ErrMissingField("masterName"))
+	}
+	errs = errs.Also(validateAddresses(s.Addresses, true))
+	return errs.Also(s.Password.Validate(ctx).ViaField("password"))
+}
+
+// Validate checks that the cluster addresses are not empty.
+func (c *RedisCluster) Validate(ctx context.Context) *apis.FieldError {
+	if c == nil {
+		return nil
+	}
+	return validateAddresses(c.Addresses, false)
+}
+
+// Validate checks that the secret and its key are named.
+func (s *RedisSecretValueFromSource) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil || s.SecretKeyRef == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if s.SecretKeyRef.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	if s.SecretKeyRef.Key == "" {
+		errs = errs.Also(apis.ErrMissingField("key"))
+	}
+	return errs.ViaField("secretKeyRef")
+}
+
+// validateAddresses checks that none of the host:port addresses is empty.
+func validateAddresses(addresses []string, required bool) *apis.FieldError {
+	if required && len(addresses) == 0 {
+		return apis.ErrMissingField("addresses")
+	}
+
+	var errs *apis.FieldError
+	for i, address := range addresses {
+		if address == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(address, "addresses", i))
+		}
+	}
+	return errs
+}
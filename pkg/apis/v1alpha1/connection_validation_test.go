@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestRedisConnection_Validate(t *testing.T) {
+	secret := func(name, key string) RedisSecretValueFromSource {
+		return RedisSecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}}
+	}
+
+	tests := []struct {
+		name string
+		conn RedisConnection
+		want *apis.FieldError
+	}{{
+		name: "address",
+		conn: RedisConnection{Address: "redis://redis.redis.svc.cluster.local:6379/1"},
+	}, {
+		name: "missing address",
+		conn: RedisConnection{},
+		want: apis.ErrMissingField("address"),
+	}, {
+		name: "invalid address",
+		conn: RedisConnection{Address: "redis.redis.svc.cluster.local:6379"},
+		want: &apis.FieldError{
+			Message: "invalid address",
+			Paths:   []string{"address"},
+			Details: "expected a redis://, rediss:// or unix:// URL",
+		},
+	}, {
+		name: "sentinel without address",
+		conn: RedisConnection{Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}}},
+	}, {
+		name: "incomplete sentinel",
+		conn: RedisConnection{Sentinel: &RedisSentinel{Addresses: []string{""}}},
+		want: apis.ErrMissingField("sentinel.masterName").
+			Also(apis.ErrInvalidArrayValue("", "sentinel.addresses", 0)),
+	}, {
+		name: "cluster without address",
+		conn: RedisConnection{Cluster: &RedisCluster{Addresses: []string{"redis-0:6379"}}},
+	}, {
+		name: "sentinel and cluster",
+		conn: RedisConnection{
+			Address:  "redis://redis:6379",
+			Sentinel: &RedisSentinel{MasterName: "mymaster", Addresses: []string{"sentinel:26379"}},
+			Cluster:  &RedisCluster{},
+		},
+		want: apis.ErrMultipleOneOf("sentinel", "cluster"),
+	}, {
+		name: "secrets",
+		conn: RedisConnection{
+			Address: "rediss://redis:6379",
+			Options: &RedisConnectionOptions{
+				Password: corev1.ObjectReference{Name: "redis"},
+				Cert:     secret("redis-tls", "tls.crt"),
+				Key:      secret("redis-tls", "tls.key"),
+				CACert:   secret("redis-ca", "ca.crt"),
+			},
+		},
+	}, {
+		name: "malformed secrets",
+		conn: RedisConnection{
+			Address: "rediss://redis:6379",
+			Options: &RedisConnectionOptions{
+				Password: corev1.ObjectReference{FieldPath: "token"},
+				Cert:     secret("", "tls.crt"),
+				CACert:   secret("redis-ca", ""),
+			},
+		},
+		want: apis.ErrMissingField("dialOptions.password.name").
+			Also(apis.ErrGeneric("expected both the client certificate and key, got one", "dialOptions.cert", "dialOptions.key")).
+			Also(apis.ErrMissingField("dialOptions.cert.secretKeyRef.name")).
+			Also(apis.ErrMissingField("dialOptions.caCert.secretKeyRef.key")),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.conn.Validate(context.Background())
+			if diff := cmp.Diff(tt.want.Error(), got.Error()); diff != "" {
+				t.Error("Unexpected validation error (-want, +got):", diff)
+			}
+		})
+	}
+}
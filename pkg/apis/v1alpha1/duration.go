@@ -0,0 +1,34 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/rickb777/date/period"
+	"knative.dev/pkg/apis"
+)
+
+// ValidateDuration checks that value is a positive ISO-8601 duration.
+func ValidateDuration(value string, fieldPath string) *apis.FieldError {
+	p, err := period.Parse(value)
+	if err != nil {
+		return apis.ErrInvalidValue(value, fieldPath, "expected an ISO-8601 duration")
+	}
+	if d, _ := p.Duration(); d <= 0 {
+		return apis.ErrInvalidValue(value, fieldPath, "expected a positive duration")
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import "testing"
+
+func TestValidateDuration(t *testing.T) {
+	if err := ValidateDuration("PT30S", "interval"); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+	for _, value := range []string{"30s", "PT0S", "-PT1S"} {
+		if err := ValidateDuration(value, "interval"); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
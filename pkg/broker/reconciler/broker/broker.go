@@ -0,0 +1,462 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	brokerreconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1/broker"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/network"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/tracker"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/eventing-redis/pkg/broker/reconciler/broker/resources"
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+	"knative.dev/eventing-redis/pkg/reconciler"
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+const (
+	// BrokerClass is the value of the eventing.knative.dev/broker.class
+	// annotation of the brokers reconciled by this controller.
+	BrokerClass = "RedisStreamBroker"
+
+	dispatcherClusterRoleName = "knative-brokers-redisstream-dispatcher"
+
+	// dispatcherShutdownPollInterval is how often a deleted broker checks
+	// that its dispatcher is gone before deleting its stream.
+	dispatcherShutdownPollInterval = 5 * time.Second
+
+	// finalizeTimeout is how long a deleted broker tries to delete its
+	// stream before giving up, for instance when Redis is gone.
+	finalizeTimeout = 10 * time.Minute
+)
+
+func newFinalizedNormal(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "RedisStreamBrokerFinalized", "RedisStreamBroker finalized: \"%s/%s\"", namespace, name)
+}
+
+func newWarningStreamNotDeleted(err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "StreamNotDeleted", "Failed to delete stream: %v", err)
+}
+
+func newWarningDeadLetterSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "DeadLetterSinkNotFound", "Dead letter sink not found: %s", string(b))
+}
+
+// Reconciler reconciles the brokers of the RedisStreamBroker class, along
+// with their triggers.
+type Reconciler struct {
+	kubeClientSet     kubernetes.Interface
+	eventingClientSet eventingclientset.Interface
+
+	triggerLister   eventinglisters.TriggerLister
+	configMapLister corev1listers.ConfigMapLister
+
+	ksr             *reconciler.KnativeServiceReconciler
+	cmr             *reconciler.ConfigMapReconciler
+	rbr             *reconciler.RoleBindingReconciler
+	sar             *reconciler.ServiceAccountReconciler
+	dispatcherImage string
+	tlsCert         string
+	sinkResolver    *resolver.URIResolver
+	tracker         tracker.Interface
+}
+
+var _ brokerreconciler.Interface = (*Reconciler)(nil)
+
+// Check that our Reconciler implements FinalizeKind.
+var _ brokerreconciler.Finalizer = (*Reconciler)(nil)
+
+// ReconcileKind reconciles the dispatcher of broker, delivering the events
+// to its triggers, and sets the status of the broker and of the triggers.
+func (r *Reconciler) ReconcileKind(ctx context.Context, broker *eventingv1.Broker) pkgreconciler.Event {
+	triggers, err := r.triggers(broker)
+	if err != nil {
+		return err
+	}
+
+	reconcileEvent := r.reconcileDispatcher(ctx, broker, triggers)
+
+	for _, trigger := range triggers {
+		trigger.Status.PropagateBrokerCondition(broker.Status.GetTopLevelCondition())
+		trigger.Status.ObservedGeneration = trigger.Generation
+		if err := r.updateTriggerStatus(ctx, trigger); err != nil {
+			logging.FromContext(ctx).Warnw("Failed to update trigger status", zap.String("trigger", trigger.Name), zap.Error(err))
+			return err
+		}
+	}
+	return reconcileEvent
+}
+
+// reconcileDispatcher reconciles the dispatcher of broker, delivering the
+// events to triggers.
+func (r *Reconciler) reconcileDispatcher(ctx context.Context, broker *eventingv1.Broker, triggers []*eventingv1.Trigger) pkgreconciler.Event {
+	config, err := r.brokerConfig(ctx, broker)
+	if err != nil {
+		broker.Status.MarkIngressFailed("InvalidConfig", "%v", err)
+		return err
+	}
+
+	deadLetterSinkURI := ""
+	if broker.Spec.Delivery != nil && broker.Spec.Delivery.DeadLetterSink != nil {
+		dlsURI, event := r.resolveDeadLetterSink(ctx, broker, broker.Namespace, broker.Spec.Delivery.DeadLetterSink)
+		if event != nil {
+			broker.Status.MarkDeadLetterSinkResolvedFailed("DeadLetterSinkNotFound", "%v", event.Error())
+			return event
+		}
+		broker.Status.MarkDeadLetterSinkResolvedSucceeded(eventingduckv1.DeliveryStatus{DeadLetterSinkURI: dlsURI})
+		deadLetterSinkURI = dlsURI.String()
+	} else {
+		broker.Status.MarkDeadLetterSinkNotConfigured()
+	}
+
+	// Replies of the subscribers are ingested by the broker.
+	replyURI := "http://" + network.GetServiceHostname(resources.DispatcherName(broker), broker.Namespace)
+
+	subscriptions := make([]dispatcher.Subscription, 0, len(triggers))
+	subscribed := make([]*eventingv1.Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		sub, ok := r.subscription(ctx, broker, trigger, deadLetterSinkURI)
+		if !ok {
+			continue
+		}
+		sub.ReplyURI = replyURI
+		subscriptions = append(subscriptions, sub)
+		subscribed = append(subscribed, trigger)
+	}
+
+	expectedServiceAccount := eventingresources.MakeServiceAccount(broker, resources.ServiceAccountName(broker))
+	sa, event := r.sar.ReconcileServiceAccount(ctx, broker, expectedServiceAccount)
+	if sa == nil {
+		broker.Status.MarkIngressFailed("NoServiceAccount", "%v", event.Error())
+		return event
+	}
+
+	expectedRoleBinding := resources.MakeRoleBinding(broker, resources.RoleBindingName(broker), dispatcherClusterRoleName)
+	rb, event := r.rbr.ReconcileRoleBinding(ctx, broker, expectedRoleBinding)
+	if rb == nil {
+		broker.Status.MarkIngressFailed("NoRoleBinding", "%v", event.Error())
+		return event
+	}
+
+	expectedSubscriptions, err := resources.MakeSubscriptions(broker, subscriptions)
+	if err != nil {
+		return err
+	}
+	cm, event := r.cmr.ReconcileConfigMap(ctx, broker, expectedSubscriptions)
+	if cm == nil {
+		broker.Status.MarkFilterFailed("NoSubscriptions", "%v", event.Error())
+		return event
+	}
+
+	expectedKService := resources.MakeDispatcher(broker, r.dispatcherImage, config.RedisConnection, config.Stream, r.tlsCert)
+	ks, event := r.ksr.ReconcileService(ctx, broker, expectedKService)
+	if ks == nil {
+		broker.Status.MarkIngressFailed("NoKnativeService", "%v", event.Error())
+		return event
+	}
+
+	if !propagateDispatcherStatus(broker, ks) {
+		for _, trigger := range subscribed {
+			trigger.Status.MarkSubscribedUnknown("DispatcherNotReady", "The broker dispatcher is not ready yet")
+		}
+		return event
+	}
+
+	// The dispatcher watches the subscriptions, the triggers are subscribed
+	// once it is ready.
+	for _, trigger := range subscribed {
+		trigger.Status.PropagateSubscriptionCondition(&apis.Condition{Status: corev1.ConditionTrue})
+	}
+	return event
+}
+
+// FinalizeKind deletes the stream of the broker, along with the consumer
+// groups of its triggers, once its dispatcher is gone.
+func (r *Reconciler) FinalizeKind(ctx context.Context, broker *eventingv1.Broker) pkgreconciler.Event {
+	// The broker is not held back forever, for instance when Redis or the
+	// namespace is gone. The stream is then left in Redis.
+	expired := time.Since(broker.DeletionTimestamp.Time) > finalizeTimeout
+
+	// Wait for the dispatcher to shutdown, so that it does not recreate the
+	// stream.
+	services := r.ksr.ServingClientSet.ServingV1().Services(broker.Namespace)
+	ks, err := services.Get(ctx, resources.DispatcherName(broker), metav1.GetOptions{})
+	if err == nil && !expired {
+		if ks.DeletionTimestamp == nil {
+			propagation := metav1.DeletePropagationForeground
+			if err := services.Delete(ctx, ks.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return controller.NewRequeueAfter(dispatcherShutdownPollInterval)
+	} else if err != nil && !apierrors.IsNotFound(err) && !expired {
+		return err
+	}
+
+	if err := r.deleteStream(ctx, broker); err != nil {
+		// A missing config or connection secret cannot be waited for.
+		if expired || apierrors.IsNotFound(err) {
+			logging.FromContext(ctx).Warnw("Giving up deleting the stream", zap.Error(err))
+			controller.GetEventRecorder(ctx).Eventf(broker, corev1.EventTypeWarning, "StreamLeft", "Gave up deleting stream, it is left in Redis: %v", err)
+			return newFinalizedNormal(broker.Namespace, broker.Name)
+		}
+		return newWarningStreamNotDeleted(err)
+	}
+	return newFinalizedNormal(broker.Namespace, broker.Name)
+}
+
+// deleteStream deletes the stream of the broker, which destroys the consumer
+// groups of its triggers.
+func (r *Reconciler) deleteStream(ctx context.Context, broker *eventingv1.Broker) error {
+	config, err := r.brokerConfig(ctx, broker)
+	if err != nil {
+		return err
+	}
+	connConfig, err := redisconn.ConfigFor(ctx, r.kubeClientSet, broker.Namespace, config.RedisConnection, r.tlsCert)
+	if err != nil {
+		return err
+	}
+	pool, err := redisconn.NewPool(connConfig)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	conn := pool.Get()
+	defer conn.Close()
+
+	logging.FromContext(ctx).Infow("Deleting stream", zap.String("stream", config.Stream))
+	_, err = conn.Do("DEL", config.Stream)
+	return err
+}
+
+// brokerConfig reads the Redis configuration of broker from the ConfigMap
+// referenced by its config.
+func (r *Reconciler) brokerConfig(ctx context.Context, broker *eventingv1.Broker) (*brokerConfig, error) {
+	ref := broker.Spec.Config
+	if ref == nil {
+		return nil, fmt.Errorf("the broker has no config")
+	}
+	if ref.Kind != "ConfigMap" {
+		return nil, fmt.Errorf("unsupported config kind %q, expected ConfigMap", ref.Kind)
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = broker.Namespace
+	}
+
+	if err := r.tracker.TrackReference(tracker.Reference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Namespace:  namespace,
+		Name:       ref.Name,
+	}, broker); err != nil {
+		return nil, err
+	}
+
+	cm, err := r.configMapLister.ConfigMaps(namespace).Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config %s/%s: %w", namespace, ref.Name, err)
+	}
+	return parseBrokerConfig(ctx, broker, cm.Data)
+}
+
+// subscription returns the subscription delivering the events of broker to
+// the subscriber of trigger, and false when its subscriber or dead letter
+// sink cannot be resolved. The delivery options of a trigger default to
+// those of the broker.
+func (r *Reconciler) subscription(ctx context.Context, broker *eventingv1.Broker, trigger *eventingv1.Trigger, deadLetterSinkURI string) (dispatcher.Subscription, bool) {
+	sub := dispatcher.Subscription{
+		UID:     string(trigger.UID),
+		Filters: trigger.Spec.Filters,
+	}
+	if trigger.Spec.Filter != nil {
+		sub.Filter = trigger.Spec.Filter.Attributes
+	}
+
+	subscriber := trigger.Spec.Subscriber.DeepCopy()
+	if subscriber.Ref != nil && subscriber.Ref.Namespace == "" {
+		subscriber.Ref.Namespace = trigger.Namespace
+	}
+	// The broker is the parent, so that changes of the subscriber requeue it.
+	subscriberURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *subscriber, broker)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Cannot resolve trigger subscriber", zap.String("trigger", trigger.Name), zap.Error(err))
+		trigger.Status.SubscriberURI = nil
+		trigger.Status.MarkSubscriberResolvedFailed("Unable to get the subscriber's URI", "%v", err)
+		trigger.Status.MarkNotSubscribed("SubscriberNotResolved", "The subscriber of the trigger cannot be resolved")
+		return sub, false
+	}
+	trigger.Status.SubscriberURI = subscriberURI
+	trigger.Status.MarkSubscriberResolvedSucceeded()
+	sub.SubscriberURI = subscriberURI.String()
+
+	delivery := broker.Spec.Delivery
+	sub.DeadLetterSinkURI = deadLetterSinkURI
+	if trigger.Spec.Delivery != nil {
+		delivery = trigger.Spec.Delivery
+		sub.DeadLetterSinkURI = ""
+		if delivery.DeadLetterSink != nil {
+			dlsURI, event := r.resolveDeadLetterSink(ctx, broker, trigger.Namespace, delivery.DeadLetterSink)
+			if event != nil {
+				trigger.Status.DeliveryStatus = eventingduckv1.DeliveryStatus{}
+				trigger.Status.MarkDeadLetterSinkResolvedFailed("DeadLetterSinkNotFound", "%v", event.Error())
+				trigger.Status.MarkNotSubscribed("DeadLetterSinkNotResolved", "The dead letter sink of the trigger cannot be resolved")
+				return sub, false
+			}
+			sub.DeadLetterSinkURI = dlsURI.String()
+		}
+	}
+	sub.SetDelivery(delivery)
+
+	if sub.DeadLetterSinkURI != "" {
+		dlsURI, _ := apis.ParseURL(sub.DeadLetterSinkURI)
+		trigger.Status.DeliveryStatus = eventingduckv1.DeliveryStatus{DeadLetterSinkURI: dlsURI}
+		trigger.Status.MarkDeadLetterSinkResolvedSucceeded()
+	} else {
+		trigger.Status.DeliveryStatus = eventingduckv1.DeliveryStatus{}
+		trigger.Status.MarkDeadLetterSinkNotConfigured()
+	}
+	trigger.Status.MarkDependencySucceeded()
+	trigger.Status.MarkOIDCIdentityCreatedNotSupported()
+	return sub, true
+}
+
+// triggers returns copies of the triggers of broker, sorted by name.
+func (r *Reconciler) triggers(broker *eventingv1.Broker) ([]*eventingv1.Trigger, error) {
+	all, err := r.triggerLister.Triggers(broker.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	triggers := make([]*eventingv1.Trigger, 0, len(all))
+	for _, trigger := range all {
+		if trigger.Spec.Broker != broker.Name || trigger.GetDeletionTimestamp() != nil {
+			continue
+		}
+		trigger = trigger.DeepCopy()
+		trigger.Status.InitializeConditions()
+		triggers = append(triggers, trigger)
+	}
+	// The order of the subscriptions must be stable for the subscriptions
+	// ConfigMap not to be updated on every reconciliation.
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].Name < triggers[j].Name
+	})
+	return triggers, nil
+}
+
+// updateTriggerStatus updates the status of trigger when it changed.
+func (r *Reconciler) updateTriggerStatus(ctx context.Context, trigger *eventingv1.Trigger) error {
+	existing, err := r.triggerLister.Triggers(trigger.Namespace).Get(trigger.Name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Status, trigger.Status) {
+		return nil
+	}
+	_, err = r.eventingClientSet.EventingV1().Triggers(trigger.Namespace).UpdateStatus(ctx, trigger, metav1.UpdateOptions{})
+	return err
+}
+
+// resolveDeadLetterSink resolves the URI of a dead letter sink, looked up in
+// namespace when its reference has none.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, broker *eventingv1.Broker, namespace string, sink *duckv1.Destination) (*apis.URL, pkgreconciler.Event) {
+	dls := sink.DeepCopy()
+	if dls.Ref != nil && dls.Ref.Namespace == "" {
+		dls.Ref.Namespace = namespace
+	}
+	dlsURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dls, broker)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Cannot resolve dead letter sink", zap.Error(err))
+		return nil, newWarningDeadLetterSinkNotFound(dls)
+	}
+	return dlsURI, nil
+}
+
+// propagateDispatcherStatus sets the conditions of broker from the
+// dispatcher Knative service, which is both its ingress and its filter.
+// It returns true when the dispatcher is ready.
+func propagateDispatcherStatus(broker *eventingv1.Broker, ks *servingv1.Service) bool {
+	manager := broker.Status.GetConditionSet().Manage(&broker.Status)
+	ready := ks.Status.GetCondition(apis.ConditionReady)
+	if !ready.IsTrue() {
+		reason, message := "DispatcherNotReady", "The dispatcher is not ready yet"
+		if ready != nil {
+			reason, message = ready.Reason, ready.Message
+		}
+		if ready == nil || ready.IsUnknown() {
+			manager.MarkUnknown(eventingv1.BrokerConditionIngress, reason, message)
+			manager.MarkUnknown(eventingv1.BrokerConditionFilter, reason, message)
+		} else {
+			broker.Status.MarkIngressFailed(reason, message)
+			broker.Status.MarkFilterFailed(reason, message)
+		}
+		broker.Status.MarkBrokerAddressableUnknown(reason, message)
+		return false
+	}
+	manager.MarkTrue(eventingv1.BrokerConditionIngress)
+	manager.MarkTrue(eventingv1.BrokerConditionFilter)
+	// The triggers consume the broker stream, there is no trigger channel.
+	manager.MarkTrueWithReason(eventingv1.BrokerConditionTriggerChannel, "RedisStream", "The triggers consume the broker stream")
+	broker.Status.MarkEventPoliciesTrueWithReason("EventPoliciesNotSupported", "Event policies are not enforced by this broker class")
+
+	if ks.Status.Address == nil {
+		broker.Status.SetAddress(nil)
+		return false
+	}
+	broker.Status.SetAddress(ks.Status.Address.DeepCopy())
+	return true
+}
+
+func (r *Reconciler) updateTLSSecret(ctx context.Context, secret *corev1.Secret) {
+	tlsSecret, err := GetTLSSecret(secret.Data)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Error reading TLS configuration", zap.Error(err))
+		return
+	}
+	// For now just override the previous config.
+	r.tlsCert = tlsSecret.TLSCertificate
+}
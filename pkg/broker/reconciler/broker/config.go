@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"sigs.k8s.io/yaml"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+const (
+	// configAddressKey is the key of the Redis address in the broker config.
+	configAddressKey = "address"
+
+	// configStreamKey is the key of the optional stream name in the broker config.
+	configStreamKey = "stream"
+
+	// configDialOptionsKey, configSentinelKey and configClusterKey are the
+	// keys of the optional dialOptions, sentinel and cluster of the Redis
+	// connection in the broker config, in YAML.
+	configDialOptionsKey = "dialOptions"
+	configSentinelKey    = "sentinel"
+	configClusterKey     = "cluster"
+)
+
+// brokerConfig is the Redis configuration of a broker, read from the
+// ConfigMap referenced by its config.
+type brokerConfig struct {
+	apisv1alpha1.RedisConnection
+	Stream string
+}
+
+// parseBrokerConfig parses the data of the config of broker. The stream
+// defaults to "knative-broker.<namespace>.<name>". The secrets referenced
+// by the connection are read in the namespace of the broker.
+func parseBrokerConfig(ctx context.Context, broker *eventingv1.Broker, data map[string]string) (*brokerConfig, error) {
+	config := &brokerConfig{
+		RedisConnection: apisv1alpha1.RedisConnection{Address: data[configAddressKey]},
+		Stream:          data[configStreamKey],
+	}
+	for _, field := range []struct {
+		key   string
+		value interface{}
+	}{
+		{configDialOptionsKey, &config.Options},
+		{configSentinelKey, &config.Sentinel},
+		{configClusterKey, &config.Cluster},
+	} {
+		if data[field.key] == "" {
+			continue
+		}
+		if err := yaml.UnmarshalStrict([]byte(data[field.key]), field.value); err != nil {
+			return nil, fmt.Errorf("invalid %q key: %w", field.key, err)
+		}
+	}
+	if err := config.RedisConnection.Validate(ctx); err != nil {
+		return nil, err
+	}
+	if config.Stream == "" {
+		config.Stream = "knative-broker." + broker.Namespace + "." + broker.Name
+	}
+	return config, nil
+}
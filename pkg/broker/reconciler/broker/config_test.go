@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestParseBrokerConfig(t *testing.T) {
+	ctx := context.Background()
+	broker := &eventingv1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "default"}}
+
+	config, err := parseBrokerConfig(ctx, broker, map[string]string{"address": "redis://redis:6379"})
+	require.NoError(t, err)
+	require.Equal(t, &brokerConfig{
+		RedisConnection: apisv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+		Stream:          "knative-broker.ns.default",
+	}, config)
+
+	config, err = parseBrokerConfig(ctx, broker, map[string]string{"address": "redis://redis:6379", "stream": "events"})
+	require.NoError(t, err)
+	require.Equal(t, "events", config.Stream)
+
+	config, err = parseBrokerConfig(ctx, broker, map[string]string{
+		"address": "rediss://redis:6379",
+		"dialOptions": `
+useTLS: true
+cert:
+  secretKeyRef:
+    name: redis-tls
+    key: tls.crt
+key:
+  secretKeyRef:
+    name: redis-tls
+    key: tls.key
+`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &apisv1alpha1.RedisConnectionOptions{
+		UseTLS: true,
+		Cert: apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"},
+			Key:                  "tls.crt",
+		}},
+		Key: apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"},
+			Key:                  "tls.key",
+		}},
+	}, config.Options)
+
+	config, err = parseBrokerConfig(ctx, broker, map[string]string{
+		"sentinel": "masterName: mymaster\naddresses: [redis-sentinel:26379]",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &apisv1alpha1.RedisSentinel{MasterName: "mymaster", Addresses: []string{"redis-sentinel:26379"}}, config.Sentinel)
+
+	config, err = parseBrokerConfig(ctx, broker, map[string]string{
+		"cluster": "addresses: [redis-0:6379, redis-1:6379]",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &apisv1alpha1.RedisCluster{Addresses: []string{"redis-0:6379", "redis-1:6379"}}, config.Cluster)
+
+	_, err = parseBrokerConfig(ctx, broker, map[string]string{"stream": "events"})
+	require.Error(t, err, "missing address")
+
+	_, err = parseBrokerConfig(ctx, broker, map[string]string{"address": "redis://redis:6379", "dialOptions": "tls: true"})
+	require.Error(t, err, "unknown field")
+
+	_, err = parseBrokerConfig(ctx, broker, map[string]string{
+		"address":  "redis://redis:6379",
+		"sentinel": "masterName: mymaster\naddresses: [redis-sentinel:26379]",
+		"cluster":  "{}",
+	})
+	require.Error(t, err, "sentinel and cluster")
+}
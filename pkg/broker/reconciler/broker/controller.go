@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	brokerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/broker"
+	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1/trigger"
+	brokerreconciler "knative.dev/eventing/pkg/client/injection/reconciler/eventing/v1/broker"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
+
+	serviceclient "knative.dev/serving/pkg/client/injection/client"
+	kserviceinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/service"
+
+	"knative.dev/eventing-redis/pkg/reconciler"
+)
+
+// envConfig will be used to extract the required environment variables using
+// github.com/kelseyhightower/envconfig. If this configuration cannot be extracted, then
+// NewController will panic.
+type envConfig struct {
+	Image string `envconfig:"BROKER_DISPATCHER_IMAGE" required:"true"`
+}
+
+// NewController initializes the controller and is called by the generated code
+// Registers event handlers to enqueue events
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	env := &envConfig{}
+	if err := envconfig.Process("", env); err != nil {
+		logging.FromContext(ctx).Panicf("unable to process RedisStreamBroker's required environment variables: %v", err)
+	}
+
+	brokerInformer := brokerinformer.Get(ctx)
+	triggerInformer := triggerinformer.Get(ctx)
+	kserviceInformer := kserviceinformer.Get(ctx)
+	configMapInformer := configmapinformer.Get(ctx)
+
+	r := &Reconciler{
+		kubeClientSet:     kubeclient.Get(ctx),
+		eventingClientSet: eventingclient.Get(ctx),
+		triggerLister:     triggerInformer.Lister(),
+		configMapLister:   configMapInformer.Lister(),
+		ksr:               &reconciler.KnativeServiceReconciler{ServingClientSet: serviceclient.Get(ctx)},
+		cmr:               &reconciler.ConfigMapReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		rbr:               &reconciler.RoleBindingReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		sar:               &reconciler.ServiceAccountReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		dispatcherImage:   env.Image,
+	}
+
+	impl := brokerreconciler.NewImpl(ctx, r, BrokerClass)
+
+	r.sinkResolver = resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
+	r.tracker = impl.Tracker
+
+	// Get TLS secret and set TLS certificate, to pass data to dispatchers.
+	// Not rolling out new dispatchers on watch change.
+	if secret, err := kubeclient.Get(ctx).CoreV1().Secrets(system.Namespace()).Get(ctx, TLSSecretName(), metav1.GetOptions{}); err == nil {
+
+		r.updateTLSSecret(ctx, secret)
+
+	} else if !apierrors.IsNotFound(err) {
+		logging.FromContext(ctx).With(zap.Error(err)).Info("Error reading TLS Secret'")
+	}
+
+	logging.FromContext(ctx).Info("Setting up event handlers")
+
+	brokerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.AnnotationFilterFunc(brokerreconciler.ClassAnnotationKey, BrokerClass, false /*allowUnset*/),
+		Handler:    controller.HandleAll(impl.Enqueue),
+	})
+
+	// A trigger requeues its broker, which dispatches to all its triggers.
+	triggerInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		if trigger, ok := obj.(*eventingv1.Trigger); ok {
+			impl.EnqueueKey(types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Spec.Broker})
+		}
+	}))
+
+	kserviceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(eventingv1.Kind("Broker")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	configMapInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(impl.Tracker.OnChanged, corev1.SchemeGroupVersion.WithKind("ConfigMap")),
+	))
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(eventingv1.Kind("Broker")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
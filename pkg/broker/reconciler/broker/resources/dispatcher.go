@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/kmeta"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+)
+
+// ttl is the number of times the events ingested by a broker, and the
+// replies to them, can be replied to the broker, as in the brokers of
+// eventing.
+const ttl = "255"
+
+// DispatcherName returns the name of the Knative service ingesting the events
+// of broker and dispatching them to its triggers.
+func DispatcherName(broker *eventingv1.Broker) string {
+	return kmeta.ChildName(broker.Name, "-broker")
+}
+
+// MakeDispatcher generates (but does not insert into K8s) the Dispatcher Knative Service for
+// RedisStream Brokers. It runs the channel dispatcher as a single replica, which both
+// ingests the events and delivers them to the triggers read from the subscriptions ConfigMap.
+func MakeDispatcher(broker *eventingv1.Broker, image string, conn apisv1alpha1.RedisConnection, stream string, tlsCert string) *servingv1.Service {
+	labels := Labels(broker.Name)
+	env := []corev1.EnvVar{{
+		Name:  "STREAM",
+		Value: stream,
+	}, {
+		Name:  "ADDRESS",
+		Value: conn.Address,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name:  "NAMESPACE",
+		Value: broker.Namespace,
+	}, {
+		Name:  "SUBSCRIPTIONS_CONFIG_MAP",
+		Value: SubscriptionsName(broker),
+	}, {
+		Name:  "TTL",
+		Value: ttl,
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+	env = append(env, eventingresources.ConnectionEnv(conn)...)
+
+	return &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: broker.Namespace,
+			Name:      DispatcherName(broker),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(broker),
+			},
+		},
+		Spec: servingv1.ServiceSpec{
+			ConfigurationSpec: servingv1.ConfigurationSpec{
+				Template: servingv1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+						Annotations: map[string]string{
+							"autoscaling.knative.dev/min-scale": "1",
+							"autoscaling.knative.dev/max-scale": "1",
+						},
+					},
+					Spec: servingv1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							ServiceAccountName: ServiceAccountName(broker),
+							Containers: []corev1.Container{
+								{
+									Name:  "dispatcher",
+									Image: image,
+									Env:   env,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
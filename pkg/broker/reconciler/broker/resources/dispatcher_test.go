@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/kmp"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestMakeDispatcher(t *testing.T) {
+	broker := &eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "broker-name",
+			Namespace: "broker-namespace",
+			UID:       "1234",
+		},
+	}
+
+	conn := apisv1alpha1.RedisConnection{
+		Address: "rediss://redis.redis.svc.cluster.local:6379",
+		Options: &apisv1alpha1.RedisConnectionOptions{
+			Password: corev1.ObjectReference{Name: "redis-password"},
+		},
+	}
+	got := MakeDispatcher(broker, "test-image", conn, "knative-broker.broker-namespace.broker-name", "")
+
+	labels := Labels(broker.Name)
+	want := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "broker-namespace",
+			Name:      "broker-name-broker",
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(broker),
+			},
+		},
+		Spec: servingv1.ServiceSpec{
+			ConfigurationSpec: servingv1.ConfigurationSpec{
+				Template: servingv1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+						Annotations: map[string]string{
+							"autoscaling.knative.dev/min-scale": "1",
+							"autoscaling.knative.dev/max-scale": "1",
+						},
+					},
+					Spec: servingv1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							ServiceAccountName: "redisstreambroker-broker-name-1234",
+							Containers: []corev1.Container{{
+								Name:  "dispatcher",
+								Image: "test-image",
+								Env: []corev1.EnvVar{{
+									Name:  "STREAM",
+									Value: "knative-broker.broker-namespace.broker-name",
+								}, {
+									Name:  "ADDRESS",
+									Value: "rediss://redis.redis.svc.cluster.local:6379",
+								}, {
+									Name:  "TLS_CERTIFICATE",
+									Value: "",
+								}, {
+									Name:  "NAMESPACE",
+									Value: "broker-namespace",
+								}, {
+									Name:  "SUBSCRIPTIONS_CONFIG_MAP",
+									Value: "broker-name-broker-subscriptions",
+								}, {
+									Name:  "TTL",
+									Value: "255",
+								}, {
+									Name:  "METRICS_DOMAIN",
+									Value: "knative.dev/eventing",
+								}, {
+									Name: "REDIS_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "redis-password"},
+											Key:                  "password",
+										},
+									},
+								}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if diff, err := kmp.SafeDiff(want, got); err != nil || diff != "" {
+		t.Error("unexpected dispatcher (-want, +got) =", diff, err)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/kmeta"
+
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+)
+
+// SubscriptionsName returns the name of the ConfigMap holding the
+// subscriptions of broker.
+func SubscriptionsName(broker *eventingv1.Broker) string {
+	return kmeta.ChildName(broker.Name, "-broker-subscriptions")
+}
+
+// MakeSubscriptions generates (but does not insert into K8s) the ConfigMap
+// holding the subscriptions the dispatcher of broker delivers events to, one
+// per trigger.
+func MakeSubscriptions(broker *eventingv1.Broker, subscriptions []dispatcher.Subscription) (*corev1.ConfigMap, error) {
+	subs, err := json.Marshal(subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: broker.Namespace,
+			Name:      SubscriptionsName(broker),
+			Labels:    Labels(broker.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(broker),
+			},
+		},
+		Data: map[string]string{
+			dispatcher.SubscriptionsKey: string(subs),
+		},
+	}, nil
+}
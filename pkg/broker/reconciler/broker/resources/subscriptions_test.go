@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/kmp"
+
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+)
+
+func TestMakeSubscriptions(t *testing.T) {
+	broker := &eventingv1.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "broker-name",
+			Namespace: "broker-namespace",
+			UID:       "1234",
+		},
+	}
+
+	got, err := MakeSubscriptions(broker, []dispatcher.Subscription{{
+		UID:           "abc",
+		SubscriberURI: "http://subscriber",
+		ReplyURI:      "http://broker-name-broker.broker-namespace.svc.cluster.local",
+		Filter:        map[string]string{"type": "com.example"},
+	}})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	want := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "broker-namespace",
+			Name:      "broker-name-broker-subscriptions",
+			Labels:    Labels(broker.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(broker),
+			},
+		},
+		Data: map[string]string{
+			"subscriptions": `[{"uid":"abc","subscriberUri":"http://subscriber","replyUri":"http://broker-name-broker.broker-namespace.svc.cluster.local","filter":{"type":"com.example"}}]`,
+		},
+	}
+
+	if diff, err := kmp.SafeDiff(want, got); err != nil || diff != "" {
+		t.Error("unexpected subscriptions (-want, +got) =", diff, err)
+	}
+}
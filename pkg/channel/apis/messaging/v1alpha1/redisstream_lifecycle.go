@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+const (
+	// RedisStreamChannelConditionReady has status True when the RedisStreamChannel is ready to receive and dispatch events.
+	RedisStreamChannelConditionReady = apis.ConditionReady
+
+	// RedisStreamChannelConditionDispatcherReady has status True when the dispatcher Knative service is ready.
+	RedisStreamChannelConditionDispatcherReady apis.ConditionType = "DispatcherReady"
+
+	// RedisStreamChannelConditionAddressable has status True when the channel has an address.
+	RedisStreamChannelConditionAddressable apis.ConditionType = "Addressable"
+
+	// RedisStreamChannelConditionDeadLetterSinkResolved has status True when the default dead letter sink,
+	// if any, is resolved.
+	RedisStreamChannelConditionDeadLetterSinkResolved apis.ConditionType = "DeadLetterSinkResolved"
+)
+
+var redisStreamChannelCondSet = apis.NewLivingConditionSet(
+	RedisStreamChannelConditionDispatcherReady,
+	RedisStreamChannelConditionAddressable,
+	RedisStreamChannelConditionDeadLetterSinkResolved,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*RedisStreamChannel) GetConditionSet() apis.ConditionSet {
+	return redisStreamChannelCondSet
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (c *RedisStreamChannel) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisStreamChannel")
+}
+
+// GetUntypedSpec returns the spec of the RedisStreamChannel.
+func (c *RedisStreamChannel) GetUntypedSpec() interface{} {
+	return c.Spec
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *RedisStreamChannelStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redisStreamChannelCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *RedisStreamChannelStatus) GetTopLevelCondition() *apis.Condition {
+	return redisStreamChannelCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *RedisStreamChannelStatus) InitializeConditions() {
+	redisStreamChannelCondSet.Manage(s).InitializeConditions()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *RedisStreamChannelStatus) IsReady() bool {
+	return redisStreamChannelCondSet.Manage(s).IsHappy()
+}
+
+// PropagateDispatcherStatus propagates the readiness and the address of the
+// dispatcher Knative service to the channel. It returns whether it is ready.
+func (s *RedisStreamChannelStatus) PropagateDispatcherStatus(ks *servingv1.Service) bool {
+	ready := ks.Status.GetCondition(apis.ConditionReady)
+	if !ready.IsTrue() {
+		if ready == nil {
+			redisStreamChannelCondSet.Manage(s).MarkUnknown(RedisStreamChannelConditionDispatcherReady, "DispatcherNotReady", "The dispatcher is not ready yet")
+		} else {
+			redisStreamChannelCondSet.Manage(s).MarkFalse(RedisStreamChannelConditionDispatcherReady, ready.Reason, ready.Message)
+		}
+		return false
+	}
+	redisStreamChannelCondSet.Manage(s).MarkTrue(RedisStreamChannelConditionDispatcherReady)
+
+	if ks.Status.Address == nil {
+		s.Address = nil
+		redisStreamChannelCondSet.Manage(s).MarkFalse(RedisStreamChannelConditionAddressable, "NoAddress", "The dispatcher has no address")
+		return false
+	}
+	s.Address = ks.Status.Address
+	redisStreamChannelCondSet.Manage(s).MarkTrue(RedisStreamChannelConditionAddressable)
+	return true
+}
+
+// MarkDispatcherFailed sets the condition that the dispatcher Knative service
+// cannot be reconciled.
+func (s *RedisStreamChannelStatus) MarkDispatcherFailed(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamChannelCondSet.Manage(s).MarkFalse(RedisStreamChannelConditionDispatcherReady, reason, messageFormat, messageA...)
+}
+
+// MarkDeadLetterSinkResolved sets the resolved default dead letter sink, if any.
+func (s *RedisStreamChannelStatus) MarkDeadLetterSinkResolved(uri *apis.URL) {
+	s.DeliveryStatus = eventingduckv1.DeliveryStatus{DeadLetterSinkURI: uri}
+	redisStreamChannelCondSet.Manage(s).MarkTrue(RedisStreamChannelConditionDeadLetterSinkResolved)
+}
+
+// MarkNoDeadLetterSink sets the condition that the default dead letter sink
+// cannot be resolved.
+func (s *RedisStreamChannelStatus) MarkNoDeadLetterSink(reason, messageFormat string, messageA ...interface{}) {
+	s.DeliveryStatus = eventingduckv1.DeliveryStatus{}
+	redisStreamChannelCondSet.Manage(s).MarkFalse(RedisStreamChannelConditionDeadLetterSinkResolved, reason, messageFormat, messageA...)
+}
+
+// MarkSubscribersReady sets the status of the subscribers served by the
+// dispatcher as ready, at their current generation.
+func (s *RedisStreamChannelStatus) MarkSubscribersReady(subscribers []eventingduckv1.SubscriberSpec) {
+	s.Subscribers = make([]eventingduckv1.SubscriberStatus, 0, len(subscribers))
+	for _, sub := range subscribers {
+		s.Subscribers = append(s.Subscribers, eventingduckv1.SubscriberStatus{
+			UID:                sub.UID,
+			ObservedGeneration: sub.Generation,
+			Ready:              corev1.ConditionTrue,
+		})
+	}
+}
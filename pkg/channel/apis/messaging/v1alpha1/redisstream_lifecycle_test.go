@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+var dispatcher = &servingv1.Service{
+	Status: servingv1.ServiceStatus{
+		Status: duckv1.Status{
+			Conditions: duckv1.Conditions{{
+				Type:   "Ready",
+				Status: "True",
+			}},
+		},
+		RouteStatusFields: servingv1.RouteStatusFields{
+			Address: &duckv1.Addressable{
+				URL: apis.HTTP("example"),
+			},
+		},
+	},
+}
+
+var _ = duck.VerifyType(&RedisStreamChannel{}, &duckv1.Conditions{})
+var _ = duck.VerifyType(&RedisStreamChannel{}, &eventingduckv1.Channelable{})
+
+func TestRedisStreamChannelGetConditionSet(t *testing.T) {
+	r := &RedisStreamChannel{}
+
+	if got, want := r.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition=%v, want=%v", got, want)
+	}
+}
+
+func TestRedisStreamChannelStreamName(t *testing.T) {
+	c := &RedisStreamChannel{}
+	c.Namespace = "ns"
+	c.Name = "orders"
+	if got, want := c.StreamName(), "knative-channel.ns.orders"; got != want {
+		t.Errorf("StreamName=%v, want=%v", got, want)
+	}
+
+	c.Spec.Stream = "orders"
+	if got, want := c.StreamName(), "orders"; got != want {
+		t.Errorf("StreamName=%v, want=%v", got, want)
+	}
+}
+
+func TestRedisStreamChannelStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *RedisStreamChannelStatus
+		want bool
+	}{{
+		name: "uninitialized",
+		s:    &RedisStreamChannelStatus{},
+		want: false,
+	}, {
+		name: "initialized",
+		s: func() *RedisStreamChannelStatus {
+			s := &RedisStreamChannelStatus{}
+			s.InitializeConditions()
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "dispatcher ready",
+		s: func() *RedisStreamChannelStatus {
+			s := &RedisStreamChannelStatus{}
+			s.InitializeConditions()
+			s.PropagateDispatcherStatus(dispatcher)
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "dispatcher ready and dead letter sink resolved",
+		s: func() *RedisStreamChannelStatus {
+			s := &RedisStreamChannelStatus{}
+			s.InitializeConditions()
+			s.PropagateDispatcherStatus(dispatcher)
+			s.MarkDeadLetterSinkResolved(nil)
+			return s
+		}(),
+		want: true,
+	}, {
+		name: "dispatcher failed",
+		s: func() *RedisStreamChannelStatus {
+			s := &RedisStreamChannelStatus{}
+			s.InitializeConditions()
+			s.PropagateDispatcherStatus(dispatcher)
+			s.MarkDeadLetterSinkResolved(nil)
+			s.MarkDispatcherFailed("Testing", "")
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "no dead letter sink",
+		s: func() *RedisStreamChannelStatus {
+			s := &RedisStreamChannelStatus{}
+			s.InitializeConditions()
+			s.PropagateDispatcherStatus(dispatcher)
+			s.MarkNoDeadLetterSink("Testing", "")
+			return s
+		}(),
+		want: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.s.IsReady()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("%s: unexpected condition (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestRedisStreamChannelStatusPropagateDispatcherStatus(t *testing.T) {
+	s := &RedisStreamChannelStatus{}
+	s.InitializeConditions()
+
+	if s.PropagateDispatcherStatus(&servingv1.Service{}) {
+		t.Error("PropagateDispatcherStatus=true for a service not ready yet")
+	}
+	want := &apis.Condition{
+		Type:    RedisStreamChannelConditionDispatcherReady,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "DispatcherNotReady",
+		Message: "The dispatcher is not ready yet",
+	}
+	ignoreTime := cmpopts.IgnoreFields(apis.Condition{}, "LastTransitionTime", "Severity")
+	if diff := cmp.Diff(want, s.GetCondition(RedisStreamChannelConditionDispatcherReady), ignoreTime); diff != "" {
+		t.Error("unexpected condition (-want, +got) =", diff)
+	}
+
+	if !s.PropagateDispatcherStatus(dispatcher) {
+		t.Error("PropagateDispatcherStatus=false for a ready service")
+	}
+	if diff := cmp.Diff(dispatcher.Status.Address, s.Address); diff != "" {
+		t.Error("unexpected address (-want, +got) =", diff)
+	}
+}
+
+func TestRedisStreamChannelStatusMarkSubscribersReady(t *testing.T) {
+	s := &RedisStreamChannelStatus{}
+	s.MarkSubscribersReady([]eventingduckv1.SubscriberSpec{{UID: "a", Generation: 2}})
+
+	want := []eventingduckv1.SubscriberStatus{{UID: "a", ObservedGeneration: 2, Ready: corev1.ConditionTrue}}
+	if diff := cmp.Diff(want, s.Subscribers); diff != "" {
+		t.Error("unexpected subscribers (-want, +got) =", diff)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// RedisStreamChannel is a Channel storing events in a Redis stream, and
+// delivering them to each subscriber through its own consumer group.
+type RedisStreamChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisStreamChannelSpec   `json:"spec,omitempty"`
+	Status RedisStreamChannelStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that RedisStreamChannel should be implementing.
+var (
+	_ runtime.Object     = (*RedisStreamChannel)(nil)
+	_ kmeta.OwnerRefable = (*RedisStreamChannel)(nil)
+	_ apis.HasSpec       = (*RedisStreamChannel)(nil)
+	_ duckv1.KRShaped    = (*RedisStreamChannel)(nil)
+)
+
+// RedisStreamChannelSpec defines the desired state of the RedisStreamChannel.
+type RedisStreamChannelSpec struct {
+	// inherits duck/v1 ChannelableSpec, which currently provides:
+	// * Subscribers - the subscribers of the channel, filled by the
+	//   Subscriptions referencing it.
+	// * Delivery - the default delivery options of the subscribers.
+	eventingduckv1.ChannelableSpec `json:",inline"`
+
+	// RedisConnection represents the address and options to connect
+	// to a Redis instance
+	apisv1alpha1.RedisConnection `json:",inline"`
+
+	// Stream is the name of the stream storing the events of the channel.
+	// Defaults to "knative-channel.<namespace>.<name>".
+	// +optional
+	Stream string `json:"stream,omitempty"`
+}
+
+// RedisStreamChannelStatus defines the observed state of RedisStreamChannel.
+type RedisStreamChannelStatus struct {
+	// inherits duck/v1 ChannelableStatus, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last
+	//   processed by the controller.
+	// * Conditions - the latest available observations of a resource's current
+	//   state.
+	// * Address - the address events are sent to.
+	// * Subscribers - the status of each subscriber.
+	// * DeadLetterSinkURI - the resolved default dead letter sink, if any.
+	eventingduckv1.ChannelableStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisStreamChannelList contains a list of RedisStreamChannels.
+type RedisStreamChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisStreamChannel `json:"items"`
+}
+
+// StreamName returns the name of the stream of the channel.
+func (c *RedisStreamChannel) StreamName() string {
+	if c.Spec.Stream != "" {
+		return c.Spec.Stream
+	}
+	return "knative-channel." + c.Namespace + "." + c.Name
+}
+
+// GetStatus retrieves the status of the RedisStreamChannel. Implements the KRShaped interface.
+func (c *RedisStreamChannel) GetStatus() *duckv1.Status {
+	return &c.Status.Status
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+// RedisStreamChannelLister helps list RedisStreamChannels.
+// All objects returned here must be treated as read-only.
+type RedisStreamChannelLister interface {
+	// List lists all RedisStreamChannels in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RedisStreamChannel, err error)
+	// RedisStreamChannels returns an object that can list and get RedisStreamChannels.
+	RedisStreamChannels(namespace string) RedisStreamChannelNamespaceLister
+	RedisStreamChannelListerExpansion
+}
+
+// redisStreamChannelLister implements the RedisStreamChannelLister interface.
+type redisStreamChannelLister struct {
+	indexer cache.Indexer
+}
+
+// NewRedisStreamChannelLister returns a new RedisStreamChannelLister.
+func NewRedisStreamChannelLister(indexer cache.Indexer) RedisStreamChannelLister {
+	return &redisStreamChannelLister{indexer: indexer}
+}
+
+// List lists all RedisStreamChannels in the indexer.
+func (s *redisStreamChannelLister) List(selector labels.Selector) (ret []*v1alpha1.RedisStreamChannel, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RedisStreamChannel))
+	})
+	return ret, err
+}
+
+// RedisStreamChannels returns an object that can list and get RedisStreamChannels.
+func (s *redisStreamChannelLister) RedisStreamChannels(namespace string) RedisStreamChannelNamespaceLister {
+	return redisStreamChannelNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RedisStreamChannelNamespaceLister helps list and get RedisStreamChannels.
+// All objects returned here must be treated as read-only.
+type RedisStreamChannelNamespaceLister interface {
+	// List lists all RedisStreamChannels in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RedisStreamChannel, err error)
+	// Get retrieves the RedisStreamChannel from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RedisStreamChannel, error)
+	RedisStreamChannelNamespaceListerExpansion
+}
+
+// redisStreamChannelNamespaceLister implements the RedisStreamChannelNamespaceLister
+// interface.
+type redisStreamChannelNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RedisStreamChannels in the indexer for a given namespace.
+func (s redisStreamChannelNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RedisStreamChannel, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RedisStreamChannel))
+	})
+	return ret, err
+}
+
+// Get retrieves the RedisStreamChannel from the indexer for a given namespace and name.
+func (s redisStreamChannelNamespaceLister) Get(name string) (*v1alpha1.RedisStreamChannel, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("redisstreamchannel"), name)
+	}
+	return obj.(*v1alpha1.RedisStreamChannel), nil
+}
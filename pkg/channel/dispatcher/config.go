@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"knative.dev/eventing/pkg/adapter/v2"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+type Config struct {
+	adapter.EnvConfig
+	redisconn.Config
+
+	Stream string `envconfig:"STREAM" required:"true"`
+
+	// SubscriptionsConfigMap is the name of the ConfigMap holding the
+	// subscriptions the events of the stream are dispatched to, in the
+	// namespace of the dispatcher. It is watched, so that subscribing does
+	// not roll out the dispatcher.
+	SubscriptionsConfigMap string `envconfig:"SUBSCRIPTIONS_CONFIG_MAP" required:"true"`
+
+	// TTL, when set, is the TTL of the events received without one. The
+	// responses of the subscribers are then dropped once the TTL of their
+	// event expires, so that the subscribers of a broker replying with the
+	// events they receive do not loop forever.
+	TTL int32 `envconfig:"TTL"`
+}
+
+// SubscriptionsKey is the key of the JSON list of subscriptions in the
+// subscriptions ConfigMap.
+const SubscriptionsKey = "subscriptions"
+
+// Subscription is a subscriber of the channel, with its delivery options.
+// Defaults are used for the options that are not set.
+type Subscription struct {
+	// UID identifies the subscription. It names its consumer group.
+	UID string `json:"uid"`
+
+	SubscriberURI string `json:"subscriberUri,omitempty"`
+	ReplyURI      string `json:"replyUri,omitempty"`
+
+	Retry             string `json:"retry,omitempty"`
+	BackoffPolicy     string `json:"backoffPolicy,omitempty"`
+	BackoffDelay      string `json:"backoffDelay,omitempty"`
+	DeadLetterSinkURI string `json:"deadLetterSinkUri,omitempty"`
+
+	// Filter and Filters select the events delivered to the subscriber, like
+	// the filter and filters of a Trigger. Filters takes precedence when both
+	// are set, and all events are delivered when neither is.
+	Filter  map[string]string                   `json:"filter,omitempty"`
+	Filters []eventingv1.SubscriptionsAPIFilter `json:"filters,omitempty"`
+}
+
+// SetDelivery sets the retry policy of the subscription from delivery, if any.
+func (s *Subscription) SetDelivery(delivery *eventingduckv1.DeliverySpec) {
+	if delivery == nil {
+		return
+	}
+	if delivery.Retry != nil {
+		s.Retry = strconv.Itoa(int(*delivery.Retry))
+	}
+	if delivery.BackoffPolicy != nil {
+		s.BackoffPolicy = string(*delivery.BackoffPolicy)
+	}
+	if delivery.BackoffDelay != nil {
+		s.BackoffDelay = *delivery.BackoffDelay
+	}
+}
+
+// ParseSubscriptions parses the JSON list of subscriptions.
+func ParseSubscriptions(value string) ([]Subscription, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var subscriptions []Subscription
+	if err := json.Unmarshal([]byte(value), &subscriptions); err != nil {
+		return nil, fmt.Errorf("invalid subscriptions: %w", err)
+	}
+	for _, sub := range subscriptions {
+		if sub.UID == "" {
+			return nil, fmt.Errorf("invalid subscriptions: missing uid")
+		}
+		if sub.SubscriberURI == "" && sub.ReplyURI == "" {
+			return nil, fmt.Errorf("invalid subscription %s: missing subscriber and reply", sub.UID)
+		}
+	}
+	return subscriptions, nil
+}
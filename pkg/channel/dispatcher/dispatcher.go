@@ -0,0 +1,401 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/eventing/pkg/eventfilter"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+	"knative.dev/eventing/pkg/eventfilter/subscriptionsapi"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
+	"knative.dev/eventing-redis/pkg/sink/receiver"
+	kadapter "knative.dev/eventing-redis/pkg/source/adapter"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// consumerRestartDelay is how long a consumer that failed waits before it
+// starts again.
+const consumerRestartDelay = 10 * time.Second
+
+// Dispatcher adds the events sent to the channel to its stream, and delivers
+// them to each subscriber through a consumer group named after the
+// subscription. The subscriptions are read from a ConfigMap, which is watched
+// to start and stop delivering events to the subscribers.
+type Dispatcher struct {
+	config  *Config
+	logger  *zap.Logger
+	podName string
+	watcher configmap.Watcher
+
+	receiver receiver.Receiver
+	client   cloudevents.Client
+
+	// newAdapter returns the adapter delivering the events of the stream to
+	// the subscriber of a subscription.
+	newAdapter func(ctx context.Context, sub Subscription) (adapter.Adapter, error)
+
+	// updateMu serializes the updates of the subscriptions, which wait for
+	// the consumers they stop without holding mu.
+	updateMu sync.Mutex
+
+	// mu guards the consumers, which are started in ctx.
+	mu        sync.Mutex
+	ctx       context.Context
+	errs      chan error
+	pool      *redis.Pool
+	consumers map[string]*consumer
+}
+
+// consumer delivers the events of the stream to the subscriber of sub.
+type consumer struct {
+	sub    Subscription
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewEnvConfig() adapter.EnvConfigAccessor {
+	return &Config{}
+}
+
+// NewDispatcher returns a dispatcher reading its subscriptions from the
+// ConfigMaps of watcher.
+func NewDispatcher(ctx context.Context, processed adapter.EnvConfigAccessor, watcher configmap.Watcher) (*Dispatcher, error) {
+	config := processed.(*Config)
+
+	client, err := cloudevents.NewClientHTTP(cehttp.WithMiddleware(receiver.RetryAfter))
+	if err != nil {
+		return nil, err
+	}
+
+	// Events are stored in the structured encoding so that the consumer
+	// groups deliver them unchanged.
+	r, err := receiver.NewReceiver(ctx, &receiver.Config{
+		EnvConfig: config.EnvConfig,
+		Config:    config.Config,
+		Stream:    config.Stream,
+		Encoding:  sinksv1alpha1.EncodingStructured,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The consumers are named after the pod, the hostname of which is its
+	// name, so that the revisions of the dispatcher overlapping during a
+	// rollout do not share consumers.
+	podName, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Dispatcher{
+		config:    config,
+		logger:    logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
+		podName:   podName,
+		watcher:   watcher,
+		receiver:  r,
+		client:    client,
+		consumers: make(map[string]*consumer),
+	}
+	d.newAdapter = d.makeAdapter
+	return d, nil
+}
+
+// Start receives the events sent to the channel and dispatches them to the
+// subscribers until ctx is done or the receiver fails.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	pool, err := redisconn.NewPool(d.config.Config)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	d.mu.Lock()
+	d.ctx, d.errs, d.pool = ctx, make(chan error, 1), pool
+	d.mu.Unlock()
+	defer d.stopConsumers()
+
+	d.watcher.Watch(d.config.SubscriptionsConfigMap, d.updateSubscriptions)
+	if err := d.watcher.Start(ctx.Done()); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := d.client.StartReceiver(ctx, d.receive); err != nil {
+			d.fail(err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-d.errs:
+		return err
+	}
+}
+
+// receive stores event in the stream, setting its TTL when the dispatcher
+// has one and the event does not.
+func (d *Dispatcher) receive(event cloudevents.Event) protocol.Result {
+	if d.config.TTL > 0 {
+		if _, ok := event.Extensions()[kadapter.TTLExtension]; !ok {
+			event.SetExtension(kadapter.TTLExtension, d.config.TTL)
+		}
+	}
+	return d.receiver.Receive(event)
+}
+
+// updateSubscriptions dispatches the events to the subscriptions of the
+// ConfigMap. Invalid subscriptions are ignored, the events being dispatched
+// to the previous ones.
+func (d *Dispatcher) updateSubscriptions(cm *corev1.ConfigMap) {
+	subscriptions, err := ParseSubscriptions(cm.Data[SubscriptionsKey])
+	if err != nil {
+		d.logger.Error("Ignoring invalid subscriptions", zap.Error(err))
+		return
+	}
+	d.setSubscriptions(subscriptions)
+}
+
+// setSubscriptions starts delivering events to the new subscriptions,
+// restarts the consumers of the changed ones, and stops those of the removed
+// ones, the consumer groups of which are destroyed. The groups are kept when
+// there is no subscription left, for instance when the ConfigMap was emptied
+// by mistake, and deleted along with the stream of the channel.
+func (d *Dispatcher) setSubscriptions(subscriptions []Subscription) {
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+
+	desired := make(map[string]Subscription, len(subscriptions))
+	for _, sub := range subscriptions {
+		desired[sub.UID] = sub
+	}
+
+	d.mu.Lock()
+	if d.ctx.Err() != nil {
+		d.mu.Unlock()
+		return
+	}
+	var stopped []*consumer
+	for uid, c := range d.consumers {
+		if sub, ok := desired[uid]; ok && reflect.DeepEqual(sub, c.sub) {
+			continue
+		}
+		c.cancel()
+		stopped = append(stopped, c)
+		delete(d.consumers, uid)
+	}
+	d.mu.Unlock()
+
+	// The consumers of the changed subscriptions are restarted once stopped,
+	// as they share their name.
+	for _, c := range stopped {
+		<-c.done
+	}
+
+	d.mu.Lock()
+	if d.ctx.Err() != nil {
+		d.mu.Unlock()
+		return
+	}
+	for _, sub := range subscriptions {
+		if _, ok := d.consumers[sub.UID]; ok {
+			continue
+		}
+		if err := d.startConsumer(sub); err != nil {
+			d.logger.Error("Cannot dispatch events to subscription", zap.String("uid", sub.UID), zap.Error(err))
+		}
+	}
+	pool, count := d.pool, len(d.consumers)
+	d.mu.Unlock()
+
+	if len(subscriptions) > 0 {
+		conn := pool.Get()
+		defer conn.Close()
+		if err := d.destroyStaleGroups(conn, subscriptions); err != nil {
+			// The stale consumer groups are destroyed on the next update.
+			d.logger.Warn("Cannot destroy the consumer groups of removed subscriptions", zap.Error(err))
+		}
+	}
+	d.logger.Info("Dispatching events", zap.Int("subscriptions", count))
+}
+
+// startConsumer starts delivering the events of the stream to the subscriber
+// of sub. A consumer that fails is restarted after a delay, without
+// affecting the other subscriptions.
+func (d *Dispatcher) startConsumer(sub Subscription) error {
+	ctx, cancel := context.WithCancel(d.ctx)
+	a, err := d.newAdapter(ctx, sub)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	c := &consumer{sub: sub, cancel: cancel, done: make(chan struct{})}
+	d.consumers[sub.UID] = c
+	go func() {
+		defer close(c.done)
+		for {
+			err := a.Start(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.Error("Cannot dispatch events to subscription, restarting", zap.String("uid", sub.UID),
+				zap.Duration("delay", consumerRestartDelay), zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(consumerRestartDelay):
+			}
+			if a, err = d.newAdapter(ctx, sub); err != nil {
+				d.logger.Error("Cannot dispatch events to subscription", zap.String("uid", sub.UID), zap.Error(err))
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// stopConsumers stops all the consumers and waits for their pending
+// deliveries.
+func (d *Dispatcher) stopConsumers() {
+	d.mu.Lock()
+	consumers := d.consumers
+	d.consumers = make(map[string]*consumer)
+	d.mu.Unlock()
+
+	for _, c := range consumers {
+		c.cancel()
+	}
+	for _, c := range consumers {
+		<-c.done
+	}
+}
+
+// fail stops the dispatcher with err, unless it is already failing.
+func (d *Dispatcher) fail(err error) {
+	select {
+	case d.errs <- err:
+	default:
+	}
+}
+
+// makeAdapter returns the adapter delivering the events of the stream to the
+// subscriber of sub. The events sent to a subscription without subscriber
+// are delivered to its reply.
+func (d *Dispatcher) makeAdapter(ctx context.Context, sub Subscription) (adapter.Adapter, error) {
+	target := sub.SubscriberURI
+	if target == "" {
+		target = sub.ReplyURI
+	}
+	client, err := cloudevents.NewClientHTTP(cloudevents.WithTarget(target))
+	if err != nil {
+		return nil, err
+	}
+	return kadapter.NewFilteredAdapter(ctx, d.adapterConfig(sub), client, d.filter(sub)), nil
+}
+
+// adapterConfig returns the configuration of the adapter delivering the events
+// of the stream to the subscriber of sub. The events sent to a subscription
+// without subscriber are delivered to its reply.
+func (d *Dispatcher) adapterConfig(sub Subscription) *kadapter.Config {
+	config := &kadapter.Config{
+		EnvConfig:      d.config.EnvConfig,
+		Config:         d.config.Config,
+		Stream:         d.config.Stream,
+		Group:          sub.UID,
+		PodName:        d.podName,
+		NumConsumers:   "1",
+		Encoding:       sourcesv1alpha1.EncodingCloudEvents,
+		GroupRetention: sourcesv1alpha1.GroupRetentionRetain,
+		Retry:          sub.Retry,
+		BackoffPolicy:  sub.BackoffPolicy,
+		BackoffDelay:   sub.BackoffDelay,
+		DeadLetterSink: sub.DeadLetterSinkURI,
+		ReplyTTL:       d.config.TTL > 0,
+	}
+	config.Sink = sub.SubscriberURI
+	config.Reply = sub.ReplyURI
+	if sub.SubscriberURI == "" {
+		config.Sink = sub.ReplyURI
+		config.Reply = ""
+	}
+	return config
+}
+
+// filter returns the filter selecting the events delivered to the subscriber
+// of sub, nil when all events are.
+func (d *Dispatcher) filter(sub Subscription) eventfilter.Filter {
+	switch {
+	case len(sub.Filters) > 0:
+		return subscriptionsapi.CreateSubscriptionsAPIFilters(d.logger, sub.Filters)
+	case len(sub.Filter) > 0:
+		return attributes.NewAttributesFilter(sub.Filter)
+	}
+	return nil
+}
+
+// destroyStaleGroups destroys the consumer groups of the subscriptions that
+// were removed from the channel.
+func (d *Dispatcher) destroyStaleGroups(conn redis.Conn, subscriptions []Subscription) error {
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", d.config.Stream))
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "no such key") {
+			return nil // The stream is created with the first consumer group.
+		}
+		return err
+	}
+
+	current := make(map[string]bool, len(subscriptions))
+	for _, sub := range subscriptions {
+		current[sub.UID] = true
+	}
+
+	var errs []error
+	for name := range groups {
+		if current[name] {
+			continue
+		}
+		d.logger.Info("Destroying consumer group", zap.String("group", name))
+		if _, err := conn.Do("XGROUP", "DESTROY", d.config.Stream, name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
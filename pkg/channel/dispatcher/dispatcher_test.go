@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing/pkg/adapter/v2"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/eventing/pkg/eventfilter"
+
+	kadapter "knative.dev/eventing-redis/pkg/source/adapter"
+)
+
+func TestParseSubscriptions(t *testing.T) {
+	subscriptions, err := ParseSubscriptions(`[{"uid":"a","subscriberUri":"http://sub","retry":"3"},{"uid":"b","replyUri":"http://reply"}]`)
+	require.NoError(t, err)
+	require.Equal(t, []Subscription{
+		{UID: "a", SubscriberURI: "http://sub", Retry: "3"},
+		{UID: "b", ReplyURI: "http://reply"},
+	}, subscriptions)
+
+	subscriptions, err = ParseSubscriptions("")
+	require.NoError(t, err)
+	require.Empty(t, subscriptions)
+
+	_, err = ParseSubscriptions(`[{"subscriberUri":"http://sub"}]`)
+	require.Error(t, err, "missing uid")
+
+	_, err = ParseSubscriptions(`[{"uid":"a"}]`)
+	require.Error(t, err, "missing subscriber and reply")
+}
+
+func TestDispatcher_AdapterConfig(t *testing.T) {
+	d := &Dispatcher{config: &Config{Stream: "mystream"}, podName: "dispatcher"}
+
+	config := d.adapterConfig(Subscription{
+		UID:               "a",
+		SubscriberURI:     "http://sub",
+		ReplyURI:          "http://reply",
+		BackoffPolicy:     "linear",
+		DeadLetterSinkURI: "http://dls",
+	})
+	require.Equal(t, "mystream", config.Stream)
+	require.Equal(t, "a", config.Group)
+	require.Equal(t, "dispatcher", config.PodName)
+	require.Equal(t, "cloudevents", config.Encoding)
+	require.Equal(t, "Retain", config.GroupRetention)
+	require.Equal(t, "http://sub", config.Sink)
+	require.Equal(t, "http://reply", config.Reply)
+	require.Equal(t, "linear", config.BackoffPolicy)
+	require.Equal(t, "http://dls", config.DeadLetterSink)
+
+	// Events of subscriptions without subscriber are sent to the reply
+	config = d.adapterConfig(Subscription{UID: "b", ReplyURI: "http://reply"})
+	require.Equal(t, &kadapter.Config{
+		EnvConfig:      config.EnvConfig,
+		Stream:         "mystream",
+		Group:          "b",
+		PodName:        "dispatcher",
+		NumConsumers:   "1",
+		Encoding:       "cloudevents",
+		GroupRetention: "Retain",
+	}, config)
+	require.Equal(t, "http://reply", config.Sink)
+}
+
+func TestDispatcher_Receive(t *testing.T) {
+	r := &fakeReceiver{}
+	d := &Dispatcher{config: &Config{TTL: 255}, receiver: r}
+	require.True(t, d.adapterConfig(Subscription{UID: "a", SubscriberURI: "http://sub"}).ReplyTTL)
+
+	event := cloudevents.NewEvent()
+	require.True(t, cloudevents.IsACK(d.receive(event)))
+	require.Equal(t, int32(255), r.events[0].Extensions()[kadapter.TTLExtension])
+
+	// Replies keep the TTL set by the subscription they are a response to.
+	event.SetExtension(kadapter.TTLExtension, int32(3))
+	require.True(t, cloudevents.IsACK(d.receive(event)))
+	require.Equal(t, int32(3), r.events[1].Extensions()[kadapter.TTLExtension])
+
+	// Channels do not set a TTL.
+	d.config.TTL = 0
+	require.True(t, cloudevents.IsACK(d.receive(cloudevents.NewEvent())))
+	require.NotContains(t, r.events[2].Extensions(), kadapter.TTLExtension)
+}
+
+// fakeReceiver records the events it receives.
+type fakeReceiver struct {
+	events []cloudevents.Event
+}
+
+func (r *fakeReceiver) Receive(event cloudevents.Event) protocol.Result {
+	r.events = append(r.events, event)
+	return protocol.ResultACK
+}
+
+func TestDispatcher_Filter(t *testing.T) {
+	d := &Dispatcher{logger: zap.NewNop()}
+	ctx := context.Background()
+
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.order")
+	event.SetSource("/orders")
+	event.SetExtension("region", "eu")
+
+	require.Nil(t, d.filter(Subscription{UID: "a"}), "no filter")
+
+	filter := d.filter(Subscription{Filter: map[string]string{"type": "com.example.order", "region": ""}})
+	require.Equal(t, eventfilter.PassFilter, filter.Filter(ctx, event))
+
+	filter = d.filter(Subscription{Filter: map[string]string{"type": "com.example.refund"}})
+	require.Equal(t, eventfilter.FailFilter, filter.Filter(ctx, event))
+
+	// Filters takes precedence over the attribute filter
+	filter = d.filter(Subscription{
+		Filter:  map[string]string{"type": "com.example.refund"},
+		Filters: []eventingv1.SubscriptionsAPIFilter{{CESQL: "region = 'eu' AND type LIKE 'com.example.%'"}},
+	})
+	require.Equal(t, eventfilter.PassFilter, filter.Filter(ctx, event))
+
+	filter = d.filter(Subscription{Filters: []eventingv1.SubscriptionsAPIFilter{{Prefix: map[string]string{"source": "/refunds"}}}})
+	require.Equal(t, eventfilter.FailFilter, filter.Filter(ctx, event))
+}
+
+func TestDispatcher_DestroyStaleGroups(t *testing.T) {
+	group := func(name string) interface{} {
+		return []interface{}{
+			[]byte("name"), []byte(name),
+			[]byte("consumers"), int64(1),
+			[]byte("pending"), int64(0),
+			[]byte("last-delivered-id"), []byte("0-0"),
+		}
+	}
+	conn := &fakeConn{replies: map[string]interface{}{
+		"XINFO":  []interface{}{group("a"), group("b")},
+		"XGROUP": int64(1),
+	}}
+	d := &Dispatcher{
+		config: &Config{Stream: "mystream"},
+		logger: zap.NewNop(),
+	}
+	subscriptions := []Subscription{{UID: "a", SubscriberURI: "http://sub"}}
+
+	require.NoError(t, d.destroyStaleGroups(conn, subscriptions))
+	require.Equal(t, [][]interface{}{
+		{"XINFO", "GROUPS", "mystream"},
+		{"XGROUP", "DESTROY", "mystream", "b"},
+	}, conn.commands)
+
+	// The stream does not exist before the first subscription
+	conn = &fakeConn{replies: map[string]interface{}{"XINFO": redis.Error("ERR no such key")}}
+	require.NoError(t, d.destroyStaleGroups(conn, subscriptions))
+}
+
+func TestDispatcher_SetSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := &fakeConn{replies: map[string]interface{}{"XINFO": redis.Error("ERR no such key")}}
+	started := make(map[string]int)
+	stopped := make(map[string]int)
+	var mu sync.Mutex // guards stopped, the consumers stopping concurrently
+	d := &Dispatcher{
+		config:    &Config{Stream: "mystream"},
+		logger:    zap.NewNop(),
+		ctx:       ctx,
+		errs:      make(chan error, 1),
+		pool:      &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+		consumers: make(map[string]*consumer),
+	}
+	d.newAdapter = func(ctx context.Context, sub Subscription) (adapter.Adapter, error) {
+		started[sub.UID]++
+		return adapterFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			stopped[sub.UID]++
+			return nil
+		}), nil
+	}
+
+	d.updateSubscriptions(&corev1.ConfigMap{Data: map[string]string{
+		SubscriptionsKey: `[{"uid":"a","subscriberUri":"http://a"},{"uid":"b","subscriberUri":"http://b"}]`,
+	}})
+	require.Equal(t, map[string]int{"a": 1, "b": 1}, started)
+	require.Empty(t, stopped)
+
+	// Unchanged subscriptions keep their consumer, changed ones restart it
+	d.setSubscriptions([]Subscription{
+		{UID: "a", SubscriberURI: "http://a"},
+		{UID: "b", SubscriberURI: "http://b", Retry: "3"},
+		{UID: "c", SubscriberURI: "http://c"},
+	})
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1}, started)
+	require.Equal(t, map[string]int{"b": 1}, stopped)
+
+	// Invalid subscriptions are ignored
+	d.updateSubscriptions(&corev1.ConfigMap{Data: map[string]string{SubscriptionsKey: `[{"uid":"a"}]`}})
+	require.Len(t, d.consumers, 3)
+
+	d.setSubscriptions([]Subscription{{UID: "c", SubscriberURI: "http://c"}})
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, stopped)
+	require.Len(t, d.consumers, 1)
+
+	// The consumer groups are kept when no subscription is left
+	conn.commands = nil
+	d.setSubscriptions(nil)
+	require.Empty(t, conn.commands)
+	require.Empty(t, d.consumers)
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1}, stopped)
+
+	d.stopConsumers()
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1}, stopped)
+}
+
+func TestDispatcher_ConsumerFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := &fakeConn{replies: map[string]interface{}{"XINFO": redis.Error("ERR no such key")}}
+	d := &Dispatcher{
+		config:    &Config{Stream: "mystream"},
+		logger:    zap.NewNop(),
+		ctx:       ctx,
+		errs:      make(chan error, 1),
+		pool:      &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+		consumers: make(map[string]*consumer),
+	}
+	failed := make(chan struct{})
+	d.newAdapter = func(ctx context.Context, sub Subscription) (adapter.Adapter, error) {
+		return adapterFunc(func(ctx context.Context) error {
+			if sub.UID == "a" {
+				close(failed)
+				return errors.New("cannot create the consumer group")
+			}
+			<-ctx.Done()
+			return nil
+		}), nil
+	}
+
+	d.setSubscriptions([]Subscription{
+		{UID: "a", SubscriberURI: "http://a"},
+		{UID: "b", SubscriberURI: "http://b"},
+	})
+	<-failed
+
+	// The failure of a subscription does not stop the dispatcher
+	select {
+	case err := <-d.errs:
+		t.Fatal("Unexpected dispatcher failure:", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	require.Len(t, d.consumers, 2)
+	d.stopConsumers()
+}
+
+func TestDispatcher_SetSubscriptionsUnlocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := &fakeConn{replies: map[string]interface{}{"XINFO": redis.Error("ERR no such key")}}
+	d := &Dispatcher{
+		config:    &Config{Stream: "mystream"},
+		logger:    zap.NewNop(),
+		ctx:       ctx,
+		errs:      make(chan error, 1),
+		pool:      &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+		consumers: make(map[string]*consumer),
+	}
+	stopping := make(chan struct{})
+	release := make(chan struct{})
+	d.newAdapter = func(ctx context.Context, sub Subscription) (adapter.Adapter, error) {
+		return adapterFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			if sub.UID == "a" {
+				// Pending deliveries
+				close(stopping)
+				<-release
+			}
+			return nil
+		}), nil
+	}
+	d.setSubscriptions([]Subscription{{UID: "a", SubscriberURI: "http://a"}})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.setSubscriptions([]Subscription{{UID: "b", SubscriberURI: "http://b"}})
+	}()
+	<-stopping
+
+	// The consumers are not locked while waiting for a consumer to stop
+	require.True(t, d.mu.TryLock())
+	d.mu.Unlock()
+
+	close(release)
+	<-done
+	require.Len(t, d.consumers, 1)
+	d.stopConsumers()
+}
+
+// adapterFunc is an adapter running a function.
+type adapterFunc func(ctx context.Context) error
+
+func (f adapterFunc) Start(ctx context.Context) error {
+	return f(ctx)
+}
+
+// fakeConn is a redis connection returning canned replies per command.
+type fakeConn struct {
+	redis.Conn
+	replies  map[string]interface{}
+	commands [][]interface{}
+}
+
+func (c *fakeConn) Err() error {
+	return nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	if err, ok := c.replies[commandName].(error); ok {
+		return nil, err
+	}
+	return c.replies[commandName], nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/kmeta"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+)
+
+func DispatcherName(channel *messagingv1alpha1.RedisStreamChannel) string {
+	return kmeta.ChildName(channel.Name, "-dispatcher")
+}
+
+// MakeDispatcher generates (but does not insert into K8s) the Dispatcher Knative Service for
+// RedisStreamChannels. The dispatcher runs as a single replica, which consumes the
+// stream on behalf of every subscription read from the subscriptions ConfigMap.
+func MakeDispatcher(channel *messagingv1alpha1.RedisStreamChannel, image string, tlsCert string) *servingv1.Service {
+	labels := Labels(channel.Name)
+	env := []corev1.EnvVar{{
+		Name:  "STREAM",
+		Value: channel.StreamName(),
+	}, {
+		Name:  "ADDRESS",
+		Value: channel.Spec.Address,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name:  "NAMESPACE",
+		Value: channel.Namespace,
+	}, {
+		Name:  "SUBSCRIPTIONS_CONFIG_MAP",
+		Value: SubscriptionsName(channel),
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+	env = append(env, eventingresources.ConnectionEnv(channel.Spec.RedisConnection)...)
+
+	return &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: channel.Namespace,
+			Name:      DispatcherName(channel),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(channel),
+			},
+		},
+		Spec: servingv1.ServiceSpec{
+			ConfigurationSpec: servingv1.ConfigurationSpec{
+				Template: servingv1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+						Annotations: map[string]string{
+							"autoscaling.knative.dev/min-scale": "1",
+							"autoscaling.knative.dev/max-scale": "1",
+						},
+					},
+					Spec: servingv1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							ServiceAccountName: ServiceAccountName(channel),
+							Containers: []corev1.Container{
+								{
+									Name:  "dispatcher",
+									Image: image,
+									Env:   env,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
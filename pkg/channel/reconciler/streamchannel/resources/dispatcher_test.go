@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/kmp"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+	"knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+)
+
+func TestMakeDispatcher(t *testing.T) {
+	channel := &v1alpha1.RedisStreamChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "channel-name",
+			Namespace: "channel-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisStreamChannelSpec{
+			RedisConnection: apisv1alpha1.RedisConnection{
+				Address: "rediss://redis.redis.svc.cluster.local:6379",
+			},
+		},
+	}
+
+	got := MakeDispatcher(channel, "test-image", "")
+
+	labels := Labels(channel.Name)
+	want := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "channel-namespace",
+			Name:      "channel-name-dispatcher",
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(channel),
+			},
+		},
+		Spec: servingv1.ServiceSpec{
+			ConfigurationSpec: servingv1.ConfigurationSpec{
+				Template: servingv1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+						Annotations: map[string]string{
+							"autoscaling.knative.dev/min-scale": "1",
+							"autoscaling.knative.dev/max-scale": "1",
+						},
+					},
+					Spec: servingv1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							ServiceAccountName: "redisstreamchannel-channel-name-1234",
+							Containers: []corev1.Container{{
+								Name:  "dispatcher",
+								Image: "test-image",
+								Env: []corev1.EnvVar{{
+									Name:  "STREAM",
+									Value: "knative-channel.channel-namespace.channel-name",
+								}, {
+									Name:  "ADDRESS",
+									Value: channel.Spec.Address,
+								}, {
+									Name:  "TLS_CERTIFICATE",
+									Value: "",
+								}, {
+									Name:  "NAMESPACE",
+									Value: "channel-namespace",
+								}, {
+									Name:  "SUBSCRIPTIONS_CONFIG_MAP",
+									Value: "channel-name-subscriptions",
+								}, {
+									Name:  "METRICS_DOMAIN",
+									Value: "knative.dev/eventing",
+								}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if diff, err := kmp.SafeDiff(want, got); err != nil || diff != "" {
+		t.Error("unexpected dispatcher (-want, +got) =", diff, err)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+)
+
+// SubscriptionsName returns the name of the ConfigMap holding the
+// subscriptions of channel.
+func SubscriptionsName(channel *messagingv1alpha1.RedisStreamChannel) string {
+	return kmeta.ChildName(channel.Name, "-subscriptions")
+}
+
+// MakeSubscriptions generates (but does not insert into K8s) the ConfigMap
+// holding the subscriptions the dispatcher of channel delivers events to.
+func MakeSubscriptions(channel *messagingv1alpha1.RedisStreamChannel, subscriptions []dispatcher.Subscription) (*corev1.ConfigMap, error) {
+	subs, err := json.Marshal(subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: channel.Namespace,
+			Name:      SubscriptionsName(channel),
+			Labels:    Labels(channel.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(channel),
+			},
+		},
+		Data: map[string]string{
+			dispatcher.SubscriptionsKey: string(subs),
+		},
+	}, nil
+}
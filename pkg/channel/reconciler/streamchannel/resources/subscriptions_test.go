@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/kmp"
+
+	"knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+)
+
+func TestMakeSubscriptions(t *testing.T) {
+	channel := &v1alpha1.RedisStreamChannel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "channel-name",
+			Namespace: "channel-namespace",
+			UID:       "1234",
+		},
+	}
+
+	got, err := MakeSubscriptions(channel, []dispatcher.Subscription{{
+		UID:               "abc",
+		SubscriberURI:     "http://subscriber",
+		DeadLetterSinkURI: "http://dls",
+	}})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	want := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "channel-namespace",
+			Name:      "channel-name-subscriptions",
+			Labels:    Labels(channel.Name),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(channel),
+			},
+		},
+		Data: map[string]string{
+			"subscriptions": `[{"uid":"abc","subscriberUri":"http://subscriber","deadLetterSinkUri":"http://dls"}]`,
+		},
+	}
+
+	if diff, err := kmp.SafeDiff(want, got); err != nil || diff != "" {
+		t.Error("unexpected subscriptions (-want, +got) =", diff, err)
+	}
+}
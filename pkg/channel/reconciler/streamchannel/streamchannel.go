@@ -0,0 +1,261 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamchannel
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	reconcilersource "knative.dev/eventing/pkg/reconciler/source"
+
+	messagingv1alpha1 "knative.dev/eventing-redis/pkg/channel/apis/messaging/v1alpha1"
+	streamchannelreconciler "knative.dev/eventing-redis/pkg/channel/client/injection/reconciler/messaging/v1alpha1/redisstreamchannel"
+	"knative.dev/eventing-redis/pkg/channel/dispatcher"
+	"knative.dev/eventing-redis/pkg/channel/reconciler/streamchannel/resources"
+	"knative.dev/eventing-redis/pkg/reconciler"
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+const (
+	component                 = "redisstreamchannel"
+	dispatcherClusterRoleName = "knative-channels-redisstream-dispatcher"
+
+	// dispatcherShutdownPollInterval is how often a deleted channel checks
+	// that its dispatcher is gone before deleting its stream.
+	dispatcherShutdownPollInterval = 5 * time.Second
+
+	// finalizeTimeout is how long a deleted channel tries to delete its
+	// stream before giving up, for instance when Redis is gone.
+	finalizeTimeout = 10 * time.Minute
+)
+
+func newFinalizedNormal(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "RedisStreamChannelFinalized", "RedisStreamChannel finalized: \"%s/%s\"", namespace, name)
+}
+
+func newWarningStreamNotDeleted(err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "StreamNotDeleted", "Failed to delete stream: %v", err)
+}
+
+func newWarningDeadLetterSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "DeadLetterSinkNotFound", "Dead letter sink not found: %s", string(b))
+}
+
+// Reconciler reconciles a streamchannel object
+type Reconciler struct {
+	kubeClientSet kubernetes.Interface
+
+	ksr             *reconciler.KnativeServiceReconciler
+	cmr             *reconciler.ConfigMapReconciler
+	rbr             *reconciler.RoleBindingReconciler
+	sar             *reconciler.ServiceAccountReconciler
+	dispatcherImage string
+	configs         reconcilersource.ConfigAccessor
+	tlsCert         string
+	sinkResolver    *resolver.URIResolver
+}
+
+var _ streamchannelreconciler.Interface = (*Reconciler)(nil)
+
+// Check that our Reconciler implements FinalizeKind.
+var _ streamchannelreconciler.Finalizer = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, channel *messagingv1alpha1.RedisStreamChannel) pkgreconciler.Event {
+	deadLetterSinkURI := ""
+	if channel.Spec.Delivery != nil && channel.Spec.Delivery.DeadLetterSink != nil {
+		dlsURI, event := r.resolveDeadLetterSink(ctx, channel, channel.Spec.Delivery.DeadLetterSink)
+		if event != nil {
+			channel.Status.MarkNoDeadLetterSink("DeadLetterSinkNotFound", "")
+			return event
+		}
+		channel.Status.MarkDeadLetterSinkResolved(dlsURI)
+		deadLetterSinkURI = dlsURI.String()
+	} else {
+		channel.Status.MarkDeadLetterSinkResolved(nil)
+	}
+
+	expectedServiceAccount := eventingresources.MakeServiceAccount(channel, resources.ServiceAccountName(channel))
+	sa, event := r.sar.ReconcileServiceAccount(ctx, channel, expectedServiceAccount)
+	if sa == nil {
+		channel.Status.MarkDispatcherFailed("NoServiceAccount", "%v", event.Error())
+		return event
+	}
+
+	expectedRoleBinding := resources.MakeRoleBinding(channel, resources.RoleBindingName(channel), dispatcherClusterRoleName)
+	rb, event := r.rbr.ReconcileRoleBinding(ctx, channel, expectedRoleBinding)
+	if rb == nil {
+		channel.Status.MarkDispatcherFailed("NoRoleBinding", "%v", event.Error())
+		return event
+	}
+
+	subscriptions, event := r.subscriptions(ctx, channel, deadLetterSinkURI)
+	if event != nil {
+		return event
+	}
+	expectedSubscriptions, err := resources.MakeSubscriptions(channel, subscriptions)
+	if err != nil {
+		return err
+	}
+	cm, event := r.cmr.ReconcileConfigMap(ctx, channel, expectedSubscriptions)
+	if cm == nil {
+		channel.Status.MarkDispatcherFailed("NoSubscriptions", "%v", event.Error())
+		return event
+	}
+
+	expectedKService := resources.MakeDispatcher(channel, r.dispatcherImage, r.tlsCert)
+	ks, event := r.ksr.ReconcileService(ctx, channel, expectedKService)
+	if ks == nil {
+		channel.Status.MarkDispatcherFailed("NoKnativeService", "%v", event.Error())
+		return event
+	}
+
+	if !channel.Status.PropagateDispatcherStatus(ks) {
+		return nil // no need to retry since the controller tracks it.
+	}
+
+	// The dispatcher watches the subscriptions, the subscribers are ready
+	// once it is.
+	channel.Status.MarkSubscribersReady(channel.Spec.Subscribers)
+	return nil
+}
+
+// FinalizeKind deletes the stream of the channel, along with the consumer
+// groups of its subscriptions, once its dispatcher is gone.
+func (r *Reconciler) FinalizeKind(ctx context.Context, channel *messagingv1alpha1.RedisStreamChannel) pkgreconciler.Event {
+	// The channel is not held back forever, for instance when Redis or the
+	// namespace is gone. The stream is then left in Redis.
+	expired := time.Since(channel.DeletionTimestamp.Time) > finalizeTimeout
+
+	// Wait for the dispatcher to shutdown, so that it does not recreate the
+	// stream.
+	services := r.ksr.ServingClientSet.ServingV1().Services(channel.Namespace)
+	ks, err := services.Get(ctx, resources.DispatcherName(channel), metav1.GetOptions{})
+	if err == nil && !expired {
+		if ks.DeletionTimestamp == nil {
+			propagation := metav1.DeletePropagationForeground
+			if err := services.Delete(ctx, ks.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return controller.NewRequeueAfter(dispatcherShutdownPollInterval)
+	} else if err != nil && !apierrors.IsNotFound(err) && !expired {
+		return err
+	}
+
+	if err := r.deleteStream(ctx, channel); err != nil {
+		// A missing connection secret cannot be waited for.
+		if expired || apierrors.IsNotFound(err) {
+			logging.FromContext(ctx).Warnw("Giving up deleting the stream", zap.Error(err))
+			controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, "StreamLeft", "Gave up deleting stream, it is left in Redis: %v", err)
+			return newFinalizedNormal(channel.Namespace, channel.Name)
+		}
+		return newWarningStreamNotDeleted(err)
+	}
+	return newFinalizedNormal(channel.Namespace, channel.Name)
+}
+
+// deleteStream deletes the stream of the channel, which destroys its consumer
+// groups.
+func (r *Reconciler) deleteStream(ctx context.Context, channel *messagingv1alpha1.RedisStreamChannel) error {
+	config, err := redisconn.ConfigFor(ctx, r.kubeClientSet, channel.Namespace, channel.Spec.RedisConnection, r.tlsCert)
+	if err != nil {
+		return err
+	}
+	pool, err := redisconn.NewPool(config)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	conn := pool.Get()
+	defer conn.Close()
+
+	logging.FromContext(ctx).Infow("Deleting stream", zap.String("stream", channel.StreamName()))
+	_, err = conn.Do("DEL", channel.StreamName())
+	return err
+}
+
+// subscriptions returns the subscriptions the dispatcher delivers events to.
+// The delivery options of a subscriber default to those of the channel.
+func (r *Reconciler) subscriptions(ctx context.Context, channel *messagingv1alpha1.RedisStreamChannel, deadLetterSinkURI string) ([]dispatcher.Subscription, pkgreconciler.Event) {
+	subscriptions := make([]dispatcher.Subscription, 0, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		sub := dispatcher.Subscription{UID: string(subscriber.UID)}
+		if subscriber.SubscriberURI != nil {
+			sub.SubscriberURI = subscriber.SubscriberURI.String()
+		}
+		if subscriber.ReplyURI != nil {
+			sub.ReplyURI = subscriber.ReplyURI.String()
+		}
+
+		delivery := channel.Spec.Delivery
+		sub.DeadLetterSinkURI = deadLetterSinkURI
+		if subscriber.Delivery != nil {
+			delivery = subscriber.Delivery
+			sub.DeadLetterSinkURI = ""
+			if delivery.DeadLetterSink != nil {
+				dlsURI, event := r.resolveDeadLetterSink(ctx, channel, delivery.DeadLetterSink)
+				if event != nil {
+					return nil, event
+				}
+				sub.DeadLetterSinkURI = dlsURI.String()
+			}
+		}
+		sub.SetDelivery(delivery)
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, nil
+}
+
+// resolveDeadLetterSink resolves the URI of a dead letter sink, looked up in
+// the namespace of the channel when its reference has none.
+func (r *Reconciler) resolveDeadLetterSink(ctx context.Context, channel *messagingv1alpha1.RedisStreamChannel, sink *duckv1.Destination) (*apis.URL, pkgreconciler.Event) {
+	dls := sink.DeepCopy()
+	if dls.Ref != nil && dls.Ref.Namespace == "" {
+		dls.Ref.Namespace = channel.GetNamespace()
+	}
+	dlsURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dls, channel)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Cannot resolve dead letter sink", zap.Error(err))
+		return nil, newWarningDeadLetterSinkNotFound(dls)
+	}
+	return dlsURI, nil
+}
+
+func (r *Reconciler) updateTLSSecret(ctx context.Context, secret *corev1.Secret) {
+	tlsSecret, err := GetTLSSecret(secret.Data)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Error reading TLS configuration", zap.Error(err))
+	}
+	// For now just override the previous config.
+	r.tlsCert = tlsSecret.TLSCertificate
+}
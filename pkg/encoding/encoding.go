@@ -0,0 +1,198 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encoding converts CloudEvents to and from the field/value pairs of
+// Redis stream entries.
+//
+// In the fields encoding, each attribute and extension of the event is stored
+// in a field named after it with the ce_ prefix, and the data is stored in the
+// data field. In the structured encoding, the whole event is stored in
+// structured mode JSON in the cloudevent field.
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// AttributePrefix prefixes the fields holding the event attributes and extensions.
+	AttributePrefix = "ce_"
+
+	// DataField holds the event data in the fields encoding.
+	DataField = "data"
+
+	// StructuredField holds the whole event in the structured encoding.
+	StructuredField = "cloudevent"
+)
+
+// EncodeFields returns the field/value pairs storing event in the fields encoding.
+func EncodeFields(event cloudevents.Event) []interface{} {
+	fields := []interface{}{
+		AttributePrefix + "specversion", event.SpecVersion(),
+		AttributePrefix + "id", event.ID(),
+		AttributePrefix + "type", event.Type(),
+		AttributePrefix + "source", event.Source(),
+	}
+	optional := []string{
+		"subject", event.Subject(),
+		"dataschema", event.DataSchema(),
+		"datacontenttype", event.DataContentType(),
+	}
+	if t := event.Time(); !t.IsZero() {
+		optional = append(optional, "time", t.Format(time.RFC3339Nano))
+	}
+	for i := 0; i < len(optional); i += 2 {
+		if optional[i+1] != "" {
+			fields = append(fields, AttributePrefix+optional[i], optional[i+1])
+		}
+	}
+
+	extensions := event.Extensions()
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, err := types.Format(extensions[name])
+		if err != nil {
+			value = fmt.Sprint(extensions[name])
+		}
+		fields = append(fields, AttributePrefix+name, value)
+	}
+
+	if data := event.Data(); data != nil {
+		fields = append(fields, DataField, data)
+	}
+	return fields
+}
+
+// EncodeStructured returns the field/value pairs storing event in the
+// structured encoding.
+func EncodeStructured(event cloudevents.Event) ([]interface{}, error) {
+	structured, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{StructuredField, structured}, nil
+}
+
+// DecodeStructured rebuilds the CloudEvent stored in structured mode JSON in
+// value. It returns nil when value holds no CloudEvent.
+func DecodeStructured(value []byte) (*cloudevents.Event, error) {
+	var probe struct {
+		SpecVersion string `json:"specversion"`
+	}
+	if err := json.Unmarshal(value, &probe); err != nil || probe.SpecVersion == "" {
+		return nil, nil
+	}
+
+	event := cloudevents.NewEvent()
+	if err := json.Unmarshal(value, &event); err != nil {
+		return nil, fmt.Errorf("invalid structured event: %w", err)
+	}
+	return &event, nil
+}
+
+// ContentType guesses the content type of a raw payload.
+func ContentType(data []byte) string {
+	switch {
+	case json.Valid(data):
+		return cloudevents.ApplicationJSON
+	case utf8.Valid(data):
+		return cloudevents.TextPlain
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Decode rebuilds the CloudEvent stored in the field/value pairs of an entry,
+// in either the fields or the structured encoding. It returns nil when the
+// entry holds no CloudEvent.
+func Decode(fieldValues []string) (*cloudevents.Event, error) {
+	if len(fieldValues)%2 != 0 {
+		return nil, fmt.Errorf("odd number of field/value items (got %d)", len(fieldValues))
+	}
+
+	attributes := make(map[string]string)
+	var data []byte
+	for i := 0; i < len(fieldValues); i += 2 {
+		field, value := fieldValues[i], fieldValues[i+1]
+		switch {
+		case field == StructuredField:
+			event := cloudevents.NewEvent()
+			if err := json.Unmarshal([]byte(value), &event); err != nil {
+				return nil, fmt.Errorf("invalid structured event: %w", err)
+			}
+			return &event, nil
+		case field == DataField:
+			data = []byte(value)
+		case strings.HasPrefix(field, AttributePrefix):
+			attributes[strings.TrimPrefix(field, AttributePrefix)] = value
+		}
+	}
+
+	specVersion, ok := attributes["specversion"]
+	if !ok {
+		return nil, nil
+	}
+
+	event := cloudevents.NewEvent(specVersion)
+	for name, value := range attributes {
+		var err error
+		switch name {
+		case "specversion":
+		case "id":
+			event.SetID(value)
+		case "type":
+			event.SetType(value)
+		case "source":
+			event.SetSource(value)
+		case "subject":
+			event.SetSubject(value)
+		case "dataschema":
+			event.SetDataSchema(value)
+		case "datacontenttype":
+			event.SetDataContentType(value)
+		case "time":
+			var t time.Time
+			if t, err = time.Parse(time.RFC3339Nano, value); err == nil {
+				event.SetTime(t)
+			}
+		default:
+			err = event.Context.SetExtension(name, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute %q: %w", name, err)
+		}
+	}
+	if data != nil {
+		event.DataEncoded = data
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	event := testEvent()
+
+	structured, err := EncodeStructured(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n, fields := range map[string][]interface{}{
+		"fields":     EncodeFields(event),
+		"structured": structured,
+	} {
+		t.Run(n, func(t *testing.T) {
+			got, err := Decode(toStrings(fields))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(event.String(), got.String()); diff != "" {
+				t.Error("unexpected event (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	want := testEvent()
+
+	structured, err := want.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		fieldValues []string
+		want        *cloudevents.Event
+		wantErr     bool
+	}{
+		"fields": {
+			fieldValues: []string{
+				"ce_specversion", "1.0",
+				"ce_id", "abc",
+				"ce_type", "com.example.order",
+				"ce_source", "/orders",
+				"ce_subject", "order-1",
+				"ce_time", "2020-08-18T18:36:54.719802342Z",
+				"ce_datacontenttype", "application/json",
+				"ce_customer", "42",
+				"data", `{"total":3}`,
+			},
+			want: &want,
+		},
+		"structured": {
+			fieldValues: []string{"cloudevent", string(structured)},
+			want:        &want,
+		},
+		"array": {
+			fieldValues: []string{"fruit", "banana"},
+		},
+		"missing required attribute": {
+			fieldValues: []string{"ce_specversion", "1.0", "ce_id", "abc"},
+			wantErr:     true,
+		},
+		"invalid structured": {
+			fieldValues: []string{"cloudevent", "{"},
+			wantErr:     true,
+		},
+		"odd number of items": {
+			fieldValues: []string{"ce_specversion"},
+			wantErr:     true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := Decode(tc.fieldValues)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.want == nil {
+				if got != nil {
+					t.Errorf("unexpected event %v", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.String(), got.String()); diff != "" {
+				t.Error("unexpected event (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeStructured(t *testing.T) {
+	want := testEvent()
+
+	structured, err := want.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		value   string
+		want    *cloudevents.Event
+		wantErr bool
+	}{
+		"structured": {
+			value: string(structured),
+			want:  &want,
+		},
+		"json": {
+			value: `{"total":3}`,
+		},
+		"text": {
+			value: "banana",
+		},
+		"invalid spec version": {
+			value:   `{"specversion":"0.1"}`,
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := DecodeStructured([]byte(tc.value))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.want == nil {
+				if got != nil {
+					t.Errorf("unexpected event %v", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want.String(), got.String()); diff != "" {
+				t.Error("unexpected event (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"json":   {data: []byte(`{"total":3}`), want: cloudevents.ApplicationJSON},
+		"text":   {data: []byte("hello"), want: cloudevents.TextPlain},
+		"binary": {data: []byte{0xff, 0xfe}, want: "application/octet-stream"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ContentType(tc.data); got != tc.want {
+				t.Errorf("ContentType() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func testEvent() cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID("abc")
+	event.SetType("com.example.order")
+	event.SetSource("/orders")
+	event.SetSubject("order-1")
+	event.SetTime(time.Date(2020, 8, 18, 18, 36, 54, 719802342, time.UTC))
+	event.SetExtension("customer", "42")
+	event.SetData(cloudevents.ApplicationJSON, map[string]int{"total": 3})
+	return event
+}
+
+// toStrings converts field/value pairs as read back from Redis.
+func toStrings(fields []interface{}) []string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		switch field := field.(type) {
+		case []byte:
+			values[i] = string(field)
+		default:
+			values[i] = field.(string)
+		}
+	}
+	return values
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// newConfigMapCreated makes a new reconciler event with event type Normal, and
+// reason ConfigMapCreated.
+func newConfigMapCreated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "ConfigMapCreated", "created config map: \"%s/%s\"", namespace, name)
+}
+
+// newConfigMapFailed makes a new reconciler event with event type Warning, and
+// reason ConfigMapFailed.
+func newConfigMapFailed(namespace, name string, err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ConfigMapFailed", "failed to create config map: \"%s/%s\", %w", namespace, name, err)
+}
+
+// newConfigMapUpdated makes a new reconciler event with event type Normal, and
+// reason ConfigMapUpdated.
+func newConfigMapUpdated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "ConfigMapUpdated", "updated config map: \"%s/%s\"", namespace, name)
+}
+
+type ConfigMapReconciler struct {
+	KubeClientSet kubernetes.Interface
+}
+
+func (r *ConfigMapReconciler) ReconcileConfigMap(ctx context.Context, owner kmeta.OwnerRefable, expected *corev1.ConfigMap) (*corev1.ConfigMap, pkgreconciler.Event) {
+	configMaps := r.KubeClientSet.CoreV1().ConfigMaps(expected.Namespace)
+	cm, err := configMaps.Get(ctx, expected.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm, err = configMaps.Create(ctx, expected, metav1.CreateOptions{})
+		if err != nil {
+			return nil, newConfigMapFailed(expected.Namespace, expected.Name, err)
+		}
+		return cm, newConfigMapCreated(cm.Namespace, cm.Name)
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting config map %q: %v", expected.Name, err)
+	} else if !metav1.IsControlledBy(cm, owner.GetObjectMeta()) {
+		return nil, fmt.Errorf("config map %q is not owned by %s %q",
+			cm.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
+	} else if !equality.Semantic.DeepEqual(expected.Data, cm.Data) {
+		cm = cm.DeepCopy()
+		cm.Data = expected.Data
+		if cm, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+			return nil, err
+		}
+		return cm, newConfigMapUpdated(cm.Namespace, cm.Name)
+	} else {
+		logging.FromContext(ctx).Debugw("Reusing existing config map", zap.Any("configMap", cm))
+	}
+	return cm, nil
+}
@@ -76,6 +76,7 @@ func (r *DeploymentReconciler) ReconcileDeployment(ctx context.Context, owner km
 		return nil, fmt.Errorf("deployment %q is not owned by %s %q",
 			ra.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
 	} else if r.podSpecChanged(expected.Spec.Template.Spec, ra.Spec.Template.Spec) {
+		ra.Spec.Template.Spec = expected.Spec.Template.Spec
 		if ra, err = r.KubeClientSet.AppsV1().Deployments(namespace).Update(ctx, ra, metav1.UpdateOptions{}); err != nil {
 			return ra, err
 		}
@@ -22,6 +22,7 @@ import (
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -45,6 +46,12 @@ func newKnativeServiceFailed(namespace, name string, err error) pkgreconciler.Ev
 	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "ServiceFailed", "failed to create service: \"%s/%s\", %w", namespace, name, err)
 }
 
+// newKnativeServiceUpdated makes a new reconciler event with event type Normal, and
+// reason ServiceUpdated.
+func newKnativeServiceUpdated(namespace, name string) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "KnativeServiceUpdated", "updated service: \"%s/%s\"", namespace, name)
+}
+
 type KnativeServiceReconciler struct {
 	ServingClientSet servingclientset.Interface
 }
@@ -62,6 +69,12 @@ func (r *KnativeServiceReconciler) ReconcileService(ctx context.Context, owner k
 	} else if !metav1.IsControlledBy(svc, owner.GetObjectMeta()) {
 		return nil, fmt.Errorf("Knative service %q is not owned by %s %q",
 			svc.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
+	} else if !equality.Semantic.DeepDerivative(expected.Spec.Template, svc.Spec.Template) {
+		svc.Spec.Template = expected.Spec.Template
+		if svc, err = r.ServingClientSet.ServingV1().Services(expected.Namespace).Update(ctx, svc, metav1.UpdateOptions{}); err != nil {
+			return nil, fmt.Errorf("error updating Knative service %q: %v", expected.Name, err)
+		}
+		return svc, newKnativeServiceUpdated(svc.Namespace, svc.Name)
 	} else {
 		logging.FromContext(ctx).Debugw("reusing existing Knative service", zap.Any("knativeService", svc))
 	}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+// ConnectionEnv returns the environment variables, besides ADDRESS, configuring
+// how the data plane connects to Redis.
+func ConnectionEnv(conn apisv1alpha1.RedisConnection) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if options := conn.Options; options != nil {
+		env = appendSecretEnv(env, "REDIS_PASSWORD", apisv1alpha1.RedisSecretValueFromSource{
+			SecretKeyRef: options.PasswordSecretKeyRef(),
+		})
+		if options.UseTLS {
+			env = append(env, corev1.EnvVar{Name: "TLS_ENABLED", Value: "true"})
+		}
+		if options.SkipVerify {
+			env = append(env, corev1.EnvVar{Name: "TLS_SKIP_VERIFY", Value: "true"})
+		}
+		env = appendSecretEnv(env, "TLS_CLIENT_CERT", options.Cert)
+		env = appendSecretEnv(env, "TLS_CLIENT_KEY", options.Key)
+		env = appendSecretEnv(env, "TLS_CA_CERT", options.CACert)
+	}
+	if sentinel := conn.Sentinel; sentinel != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "SENTINEL_MASTER_NAME",
+			Value: sentinel.MasterName,
+		}, corev1.EnvVar{
+			Name:  "SENTINEL_ADDRESSES",
+			Value: strings.Join(sentinel.Addresses, ","),
+		})
+		if sentinel.Password != nil {
+			env = appendSecretEnv(env, "SENTINEL_PASSWORD", *sentinel.Password)
+		}
+	}
+	if cluster := conn.Cluster; cluster != nil {
+		env = append(env, corev1.EnvVar{
+			Name:  "CLUSTER_MODE",
+			Value: "true",
+		})
+		if len(cluster.Addresses) > 0 {
+			env = append(env, corev1.EnvVar{
+				Name:  "CLUSTER_ADDRESSES",
+				Value: strings.Join(cluster.Addresses, ","),
+			})
+		}
+	}
+	return env
+}
+
+// appendSecretEnv appends the environment variable name, set from the secret
+// key referenced by value if any.
+func appendSecretEnv(env []corev1.EnvVar, name string, value apisv1alpha1.RedisSecretValueFromSource) []corev1.EnvVar {
+	if value.SecretKeyRef == nil {
+		return env
+	}
+	return append(env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: value.SecretKeyRef,
+		},
+	})
+}
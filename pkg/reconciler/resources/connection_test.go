@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestConnectionEnv(t *testing.T) {
+	password := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "sentinel"},
+		Key:                  "password",
+	}
+	caCert := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "redis-tls"},
+		Key:                  "ca.crt",
+	}
+
+	tests := map[string]struct {
+		conn apisv1alpha1.RedisConnection
+		want []corev1.EnvVar
+	}{
+		"address only": {
+			conn: apisv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+		},
+		"sentinel": {
+			conn: apisv1alpha1.RedisConnection{
+				Sentinel: &apisv1alpha1.RedisSentinel{
+					MasterName: "mymaster",
+					Addresses:  []string{"sentinel-0:26379", "sentinel-1:26379"},
+					Password:   &apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: password},
+				},
+			},
+			want: []corev1.EnvVar{{
+				Name:  "SENTINEL_MASTER_NAME",
+				Value: "mymaster",
+			}, {
+				Name:  "SENTINEL_ADDRESSES",
+				Value: "sentinel-0:26379,sentinel-1:26379",
+			}, {
+				Name:      "SENTINEL_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: password},
+			}},
+		},
+		"dial options": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "rediss://redis:6379",
+				Options: &apisv1alpha1.RedisConnectionOptions{
+					Password:   corev1.ObjectReference{Name: "redis"},
+					UseTLS:     true,
+					SkipVerify: true,
+					CACert:     apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: caCert},
+				},
+			},
+			want: []corev1.EnvVar{{
+				Name: "REDIS_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "redis"},
+					Key:                  "password",
+				}},
+			}, {
+				Name:  "TLS_ENABLED",
+				Value: "true",
+			}, {
+				Name:  "TLS_SKIP_VERIFY",
+				Value: "true",
+			}, {
+				Name:      "TLS_CA_CERT",
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: caCert},
+			}},
+		},
+		"cluster": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "redis://redis-0:6379",
+				Cluster: &apisv1alpha1.RedisCluster{
+					Addresses: []string{"redis-1:6379"},
+				},
+			},
+			want: []corev1.EnvVar{{
+				Name:  "CLUSTER_MODE",
+				Value: "true",
+			}, {
+				Name:  "CLUSTER_ADDRESSES",
+				Value: "redis-1:6379",
+			}},
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := ConnectionEnv(tc.conn)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected env (-want, +got) =", diff)
+			}
+		})
+	}
+}
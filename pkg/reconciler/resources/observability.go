@@ -0,0 +1,32 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ObservabilityEnv returns the environment variable making the data plane
+// export its metrics in the Prometheus format on port.
+func ObservabilityEnv(port int) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name:  "K_OBSERVABILITY_CONFIG",
+		Value: fmt.Sprintf(`{"metrics":{"protocol":"prometheus","endpoint":":%d"}}`, port),
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"knative.dev/pkg/observability"
+	"knative.dev/pkg/observability/metrics"
+)
+
+func TestObservabilityEnv(t *testing.T) {
+	env := ObservabilityEnv(9090)
+	if env.Name != "K_OBSERVABILITY_CONFIG" {
+		t.Errorf("unexpected name %q", env.Name)
+	}
+
+	cfg := &observability.Config{}
+	if err := json.Unmarshal([]byte(env.Value), cfg); err != nil {
+		t.Fatal("cannot parse the observability configuration:", err)
+	}
+	if cfg.Metrics.Protocol != metrics.ProtocolPrometheus || cfg.Metrics.Endpoint != ":9090" {
+		t.Errorf("unexpected metrics configuration %+v", cfg.Metrics)
+	}
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// clusterMaxRedirects bounds the MOVED and ASK redirections followed by a command.
+const clusterMaxRedirects = 5
+
+// cluster keeps track of the master serving each slot of a Redis Cluster.
+type cluster struct {
+	seeds []string
+	dial  func(addr string) (redis.Conn, error)
+
+	mu    sync.RWMutex
+	slots []string // address of the master serving each slot
+	stale bool
+}
+
+func newCluster(seeds []string, dial func(addr string) (redis.Conn, error)) *cluster {
+	return &cluster{
+		seeds: seeds,
+		dial:  dial,
+		slots: make([]string, slotCount),
+		stale: true,
+	}
+}
+
+// dialConn returns a connection routing commands to the cluster nodes.
+func (cl *cluster) dialConn() (redis.Conn, error) {
+	c := &clusterConn{cluster: cl, conns: make(map[string]redis.Conn)}
+	if _, err := c.Do("PING"); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// addr returns the address of the master serving slot.
+func (cl *cluster) addr(slot int) (string, error) {
+	if err := cl.refreshIfStale(); err != nil {
+		return "", err
+	}
+	cl.mu.RLock()
+	defer cl.mu.RUnlock()
+	if cl.slots[slot] == "" {
+		return "", fmt.Errorf("slot %d is not served by any node", slot)
+	}
+	return cl.slots[slot], nil
+}
+
+// anyAddr returns the address of a node, for commands without key.
+func (cl *cluster) anyAddr() (string, error) {
+	if err := cl.refreshIfStale(); err != nil {
+		return "", err
+	}
+	return cl.nodes()[0], nil
+}
+
+// moved records that slot is now served by addr. The whole topology is
+// reloaded by the next command since resharding usually moves many slots.
+func (cl *cluster) moved(slot int, addr string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.slots[slot] = addr
+	cl.stale = true
+}
+
+func (cl *cluster) markStale() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.stale = true
+}
+
+func (cl *cluster) refreshIfStale() error {
+	cl.mu.RLock()
+	stale := cl.stale
+	cl.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+	return cl.refresh()
+}
+
+// refresh reloads the topology from the first node that answers.
+func (cl *cluster) refresh() error {
+	var errs []string
+	for _, addr := range cl.nodes() {
+		slots, err := cl.querySlots(addr)
+		if err == nil {
+			cl.mu.Lock()
+			cl.slots = slots
+			cl.stale = false
+			cl.mu.Unlock()
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", addr, err))
+	}
+	return fmt.Errorf("cannot get cluster slots: %s", strings.Join(errs, "; "))
+}
+
+// nodes returns the known masters followed by the seeds.
+func (cl *cluster) nodes() []string {
+	seen := make(map[string]bool)
+	var nodes []string
+	add := func(addr string) {
+		if addr != "" && !seen[addr] {
+			seen[addr] = true
+			nodes = append(nodes, addr)
+		}
+	}
+
+	cl.mu.RLock()
+	for _, addr := range cl.slots {
+		add(addr)
+	}
+	cl.mu.RUnlock()
+	for _, addr := range cl.seeds {
+		add(addr)
+	}
+	return nodes
+}
+
+func (cl *cluster) querySlots(addr string) ([]string, error) {
+	c, err := cl.dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	return parseClusterSlots(redis.Values(c.Do("CLUSTER", "SLOTS")))
+}
+
+// parseClusterSlots converts a CLUSTER SLOTS reply to the address of the
+// master serving each slot.
+func parseClusterSlots(ranges []interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]string, slotCount)
+	for _, r := range ranges {
+		values, err := redis.Values(r, nil)
+		if err != nil || len(values) < 3 {
+			return nil, fmt.Errorf("invalid slot range %v", r)
+		}
+		start, err1 := redis.Int(values[0], nil)
+		end, err2 := redis.Int(values[1], nil)
+		master, err3 := redis.Values(values[2], nil)
+		if err1 != nil || err2 != nil || err3 != nil || len(master) < 2 || start < 0 || start > end || end >= slotCount {
+			return nil, fmt.Errorf("invalid slot range %v", r)
+		}
+		host, err1 := redis.String(master[0], nil)
+		port, err2 := redis.Int(master[1], nil)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid node %v", master)
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		for slot := start; slot <= end; slot++ {
+			slots[slot] = addr
+		}
+	}
+	return slots, nil
+}
+
+// clusterConn sends each command to the master serving the slot of its key,
+// following MOVED and ASK redirections. Pipelined commands are sent one by
+// one when their reply is received, since they may target different nodes.
+//
+// Subscriptions are served by a single node, since published messages are
+// broadcast to the whole cluster. Once subscribed, all the commands are sent
+// to that node.
+//
+// It becomes unusable once the connection to a node fails, so that the pool
+// discards it and callers know to reconnect.
+type clusterConn struct {
+	cluster    *cluster
+	conns      map[string]redis.Conn
+	pending    []clusterCommand
+	subscriber redis.Conn
+	err        error
+}
+
+type clusterCommand struct {
+	name string
+	args []interface{}
+}
+
+func (c *clusterConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var addr string
+	var err error
+	if key, ok := commandKey(commandName, args); ok {
+		addr, err = c.cluster.addr(keySlot(key))
+	} else {
+		addr, err = c.cluster.anyAddr()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	asking := false
+	for redirects := 0; ; redirects++ {
+		conn, err := c.conn(addr)
+		if err != nil {
+			return nil, c.fail(err)
+		}
+		if asking {
+			conn.Send("ASKING")
+		}
+		reply, err := conn.Do(commandName, args...)
+		if conn.Err() != nil {
+			delete(c.conns, addr)
+			conn.Close()
+			return nil, c.fail(err)
+		}
+
+		kind, slot, target := parseRedirection(err)
+		if kind == "" || redirects == clusterMaxRedirects {
+			return reply, err
+		}
+		if kind == "MOVED" {
+			c.cluster.moved(slot, target)
+		}
+		asking = kind == "ASK"
+		addr = target
+	}
+}
+
+func (c *clusterConn) Send(commandName string, args ...interface{}) error {
+	if c.err == nil && (c.subscriber != nil || isSubscribeCommand(commandName)) {
+		if err := c.subscribe(); err != nil {
+			return err
+		}
+		return c.check(c.subscriber, c.subscriber.Send(commandName, args...))
+	}
+	c.pending = append(c.pending, clusterCommand{name: commandName, args: args})
+	return c.err
+}
+
+func (c *clusterConn) Flush() error {
+	if c.err == nil && c.subscriber != nil {
+		return c.check(c.subscriber, c.subscriber.Flush())
+	}
+	return c.err
+}
+
+func (c *clusterConn) Receive() (interface{}, error) {
+	if c.err == nil && c.subscriber != nil && len(c.pending) == 0 {
+		reply, err := c.subscriber.Receive()
+		return reply, c.check(c.subscriber, err)
+	}
+	if len(c.pending) == 0 {
+		return nil, errors.New("no pending command")
+	}
+	cmd := c.pending[0]
+	c.pending = c.pending[1:]
+	return c.Do(cmd.name, cmd.args...)
+}
+
+func (c *clusterConn) Err() error {
+	return c.err
+}
+
+func (c *clusterConn) Close() error {
+	if c.subscriber != nil {
+		c.subscriber.Close()
+	}
+	for addr, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, addr)
+	}
+	return nil
+}
+
+func (c *clusterConn) conn(addr string) (redis.Conn, error) {
+	if conn, ok := c.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := c.cluster.dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	c.conns[addr] = conn
+	return conn, nil
+}
+
+// subscribe connects to the node serving the subscriptions. It is not shared
+// with other commands, which are rejected by subscribed connections.
+func (c *clusterConn) subscribe() error {
+	if c.subscriber != nil {
+		return nil
+	}
+	addr, err := c.cluster.anyAddr()
+	if err != nil {
+		return err
+	}
+	conn, err := c.cluster.dial(addr)
+	if err != nil {
+		return c.fail(err)
+	}
+	c.subscriber = conn
+	return nil
+}
+
+// check marks the connection as broken when conn failed.
+func (c *clusterConn) check(conn redis.Conn, err error) error {
+	if conn.Err() != nil {
+		return c.fail(err)
+	}
+	return err
+}
+
+func (c *clusterConn) fail(err error) error {
+	c.cluster.markStale()
+	c.err = err
+	return err
+}
+
+// isSubscribeCommand returns true if commandName puts the connection in the
+// subscribed state.
+func isSubscribeCommand(commandName string) bool {
+	switch strings.ToUpper(commandName) {
+	case "SUBSCRIBE", "PSUBSCRIBE":
+		return true
+	}
+	return false
+}
+
+// parseRedirection returns the kind (MOVED or ASK), slot and target address
+// of a redirection error, or an empty kind for any other error.
+func parseRedirection(err error) (string, int, string) {
+	var redisErr redis.Error
+	if !errors.As(err, &redisErr) {
+		return "", 0, ""
+	}
+	fields := strings.Fields(string(redisErr))
+	if len(fields) != 3 || (fields[0] != "MOVED" && fields[0] != "ASK") {
+		return "", 0, ""
+	}
+	slot, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return "", 0, ""
+	}
+	return fields[0], slot, fields[2]
+}
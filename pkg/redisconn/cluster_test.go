@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseClusterSlots(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("10.0.0.1"), int64(6379), []byte("id1")}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("10.0.0.2"), int64(6379), []byte("id2")}, []interface{}{[]byte("10.0.0.3"), int64(6379), []byte("id3")}},
+	}
+	slots, err := parseClusterSlots(reply, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slots[0] != "10.0.0.1:6379" || slots[8191] != "10.0.0.1:6379" || slots[8192] != "10.0.0.2:6379" || slots[16383] != "10.0.0.2:6379" {
+		t.Errorf("unexpected slots %v %v %v %v", slots[0], slots[8191], slots[8192], slots[16383])
+	}
+
+	if _, err := parseClusterSlots([]interface{}{[]interface{}{int64(0), int64(16384), []interface{}{[]byte("10.0.0.1"), int64(6379)}}}, nil); err == nil {
+		t.Error("expected an error for an invalid slot range")
+	}
+}
+
+func TestClusterConn(t *testing.T) {
+	// Slots 0-8191 are served by node a, 8192-16383 by node b. The slot of
+	// "mystream" is 11084.
+	nodes := map[string]*fakeNode{
+		"a:6379": {},
+		"b:6379": {},
+	}
+	slotsReply := func() (interface{}, error) {
+		return []interface{}{
+			[]interface{}{int64(0), int64(8191), []interface{}{[]byte("a"), int64(6379)}},
+			[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("b"), int64(6379)}},
+		}, nil
+	}
+	for _, node := range nodes {
+		node.replies = map[string]func() (interface{}, error){"CLUSTER": slotsReply}
+	}
+
+	cl := newCluster([]string{"a:6379"}, func(addr string) (redis.Conn, error) {
+		node, ok := nodes[addr]
+		if !ok {
+			return nil, fmt.Errorf("unknown node %s", addr)
+		}
+		return node, nil
+	})
+
+	conn, err := cl.dialConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("route", func(t *testing.T) {
+		nodes["b:6379"].replies["XADD"] = func() (interface{}, error) { return []byte("1-0"), nil }
+		if _, err := conn.Do("XADD", "mystream", "*", "f", "v"); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"XADD"}, nodes["b:6379"].take()); diff != "" {
+			t.Error("unexpected commands on node b (-want, +got) =", diff)
+		}
+	})
+
+	t.Run("ask", func(t *testing.T) {
+		nodes["b:6379"].replies["XACK"] = func() (interface{}, error) { return nil, redis.Error("ASK 11084 a:6379") }
+		nodes["a:6379"].replies["XACK"] = func() (interface{}, error) { return int64(1), nil }
+		nodes["a:6379"].take()
+		if _, err := conn.Do("XACK", "mystream", "mygroup", "1-0"); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"ASKING", "XACK"}, nodes["a:6379"].take()); diff != "" {
+			t.Error("unexpected commands on node a (-want, +got) =", diff)
+		}
+		if addr, _ := cl.addr(keySlot("mystream")); addr != "b:6379" {
+			t.Errorf("ASK changed the slot owner to %s", addr)
+		}
+	})
+
+	t.Run("moved", func(t *testing.T) {
+		nodes["b:6379"].replies["XADD"] = func() (interface{}, error) { return nil, redis.Error("MOVED 11084 a:6379") }
+		nodes["a:6379"].replies["XADD"] = func() (interface{}, error) { return []byte("2-0"), nil }
+		movedSlots := func() (interface{}, error) {
+			return []interface{}{
+				[]interface{}{int64(0), int64(16383), []interface{}{[]byte("a"), int64(6379)}},
+			}, nil
+		}
+		nodes["a:6379"].replies["CLUSTER"] = movedSlots
+		nodes["b:6379"].replies["CLUSTER"] = movedSlots
+
+		reply, err := conn.Do("XADD", "mystream", "*", "f", "v")
+		if err != nil || string(reply.([]byte)) != "2-0" {
+			t.Fatalf("unexpected reply %v, %v", reply, err)
+		}
+		if addr, _ := cl.addr(keySlot("mystream")); addr != "a:6379" {
+			t.Errorf("MOVED did not change the slot owner, got %s", addr)
+		}
+	})
+
+	t.Run("subscribe", func(t *testing.T) {
+		conn, err := cl.dialConn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		nodes["a:6379"].take()
+		nodes["a:6379"].received = []interface{}{
+			[]interface{}{[]byte("subscribe"), []byte("mychannel"), int64(1)},
+			[]interface{}{[]byte("message"), []byte("mychannel"), []byte("hello")},
+		}
+
+		psc := redis.PubSubConn{Conn: conn}
+		if err := psc.Subscribe("mychannel"); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := psc.Receive().(redis.Subscription); !ok {
+			t.Fatal("expected a subscription")
+		}
+		msg, ok := psc.Receive().(redis.Message)
+		if !ok || msg.Channel != "mychannel" || string(msg.Data) != "hello" {
+			t.Fatalf("unexpected message %v", msg)
+		}
+		if diff := cmp.Diff([]string{"SUBSCRIBE"}, nodes["a:6379"].pending); diff != "" {
+			t.Error("unexpected commands on node a (-want, +got) =", diff)
+		}
+	})
+
+	t.Run("broken", func(t *testing.T) {
+		nodes["a:6379"].err = fmt.Errorf("connection reset")
+		if _, err := conn.Do("XADD", "mystream", "*", "f", "v"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if conn.Err() == nil {
+			t.Error("connection not marked as broken")
+		}
+	})
+}
+
+type fakeNode struct {
+	redis.Conn
+	replies  map[string]func() (interface{}, error)
+	commands []string
+	pending  []string
+	received []interface{}
+	err      error
+}
+
+func (n *fakeNode) Send(commandName string, args ...interface{}) error {
+	n.pending = append(n.pending, commandName)
+	return nil
+}
+
+func (n *fakeNode) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	n.commands = append(n.commands, n.pending...)
+	n.commands = append(n.commands, commandName)
+	n.pending = nil
+	if reply, ok := n.replies[commandName]; ok {
+		return reply()
+	}
+	return []byte("OK"), nil
+}
+
+func (n *fakeNode) Flush() error {
+	return n.err
+}
+
+func (n *fakeNode) Receive() (interface{}, error) {
+	if n.err != nil {
+		return nil, n.err
+	}
+	if len(n.received) == 0 {
+		return nil, fmt.Errorf("no reply")
+	}
+	reply := n.received[0]
+	n.received = n.received[1:]
+	return reply, nil
+}
+
+func (n *fakeNode) Err() error {
+	return n.err
+}
+
+func (n *fakeNode) Close() error {
+	return nil
+}
+
+// take returns the commands received by the node since the last call.
+func (n *fakeNode) take() []string {
+	commands := n.commands
+	n.commands = nil
+	return commands
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redisconn creates the Redis connections used by the receive
+// adapter and the sink receiver.
+package redisconn
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	redisParse "github.com/go-redis/redis/v8"
+	"github.com/gomodule/redigo/redis"
+)
+
+// Config is the Redis connection configuration, read from the environment.
+type Config struct {
+	Address string `envconfig:"ADDRESS" required:"true"`
+
+	// TLSCertificate is the cluster-wide certificate of the server CA. TLS
+	// is used whenever it holds a certificate, and the server is verified
+	// against it when the resource sets no CA certificate.
+	TLSCertificate string `envconfig:"TLS_CERTIFICATE" required:"true"`
+
+	// Dial options of the resource. The password, certificates and key are
+	// read from the secrets referenced by the resource. TLS is also used
+	// when the address scheme is rediss.
+	Password      string `envconfig:"REDIS_PASSWORD"`
+	TLSEnabled    bool   `envconfig:"TLS_ENABLED"`
+	TLSSkipVerify bool   `envconfig:"TLS_SKIP_VERIFY"`
+	TLSClientCert string `envconfig:"TLS_CLIENT_CERT"`
+	TLSClientKey  string `envconfig:"TLS_CLIENT_KEY"`
+	TLSCACert     string `envconfig:"TLS_CA_CERT"`
+
+	// Sentinel options. Sentinel mode is enabled when the master name is set.
+	SentinelMasterName string   `envconfig:"SENTINEL_MASTER_NAME"`
+	SentinelAddresses  []string `envconfig:"SENTINEL_ADDRESSES"`
+	SentinelPassword   string   `envconfig:"SENTINEL_PASSWORD"`
+
+	// Cluster options. The host of Address is used as seed node, in addition
+	// to the cluster addresses.
+	ClusterMode      bool     `envconfig:"CLUSTER_MODE"`
+	ClusterAddresses []string `envconfig:"CLUSTER_ADDRESSES"`
+
+	// OmitUsername leaves the username of the address out of AUTH, and
+	// LegacyTLSRequiresPassword only uses the cluster-wide certificate when a
+	// password is set. They are set by the sink receiver, which always
+	// connected this way.
+	OmitUsername              bool `ignored:"true"`
+	LegacyTLSRequiresPassword bool `ignored:"true"`
+}
+
+// NewPool returns a pool of connections to the Redis instance described by config.
+func NewPool(config Config) (*redis.Pool, error) {
+	if config.SentinelMasterName != "" && config.ClusterMode {
+		return nil, errors.New("sentinel and cluster modes are mutually exclusive")
+	}
+
+	opt, err := config.parseAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	password := opt.Password
+	if config.Password != "" {
+		password = config.Password
+	}
+	options := []redis.DialOption{
+		redis.DialDatabase(opt.DB),
+		redis.DialPassword(password),
+	}
+	if !config.OmitUsername {
+		options = append(options, redis.DialUsername(opt.Username))
+	}
+
+	tlsConfig, err := config.tlsConfig(opt.TLSConfig != nil, password)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		options = append(options,
+			redis.DialTLSConfig(tlsConfig),
+			redis.DialUseTLS(true),
+		)
+	}
+
+	dial := func() (redis.Conn, error) {
+		return redis.Dial("tcp", opt.Addr, options...)
+	}
+	if config.SentinelMasterName != "" {
+		s := &sentinel{
+			masterName: config.SentinelMasterName,
+			addresses:  config.SentinelAddresses,
+			password:   config.SentinelPassword,
+			options:    options,
+		}
+		dial = s.dialMaster
+	}
+	if config.ClusterMode {
+		var seeds []string
+		if config.Address != "" {
+			seeds = append(seeds, opt.Addr)
+		}
+		seeds = append(seeds, config.ClusterAddresses...)
+		cl := newCluster(seeds, func(addr string) (redis.Conn, error) {
+			return redis.Dial("tcp", addr, options...)
+		})
+		dial = cl.dialConn
+	}
+
+	return &redis.Pool{
+		// Maximum number of idle connections in the pool.
+		MaxIdle: 80,
+		// max number of connections
+		MaxActive: 12000,
+		// Dial is an application supplied function for creating and
+		// configuring a connection.
+		Dial: dial,
+	}, nil
+}
+
+// Database returns the database selected by the address.
+func (config Config) Database() (int, error) {
+	opt, err := config.parseAddress()
+	if err != nil {
+		return 0, err
+	}
+	return opt.DB, nil
+}
+
+func (config Config) parseAddress() (*redisParse.Options, error) {
+	address := config.Address
+	if address == "" && (config.SentinelMasterName != "" || len(config.ClusterAddresses) > 0) {
+		// The nodes are discovered, there is nothing else to configure.
+		address = "redis://"
+	}
+	return redisParse.ParseURL(address)
+}
+
+// tlsConfig returns the TLS configuration of the connections, or nil when
+// TLS is not used. rediss tells whether the address scheme is rediss.
+func (config Config) tlsConfig(rediss bool, password string) (*tls.Config, error) {
+	legacyRoots := config.legacyRoots(password)
+	if !config.TLSEnabled && !rediss && legacyRoots == nil && config.TLSCACert == "" && config.TLSClientCert == "" {
+		return nil, nil
+	}
+
+	// The server certificate is verified against the CA certificate of the
+	// resource, or else against the system roots and, when it is set, the
+	// cluster-wide certificate.
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.TLSSkipVerify,
+	}
+
+	switch {
+	case config.TLSCACert != "":
+		roots := x509.NewCertPool()
+		if ok := roots.AppendCertsFromPEM([]byte(config.TLSCACert)); !ok {
+			return nil, errors.New("invalid TLS CA certificate")
+		}
+		tlsConfig.RootCAs = roots
+	case legacyRoots != nil:
+		tlsConfig.RootCAs = legacyRoots
+	}
+
+	if config.TLSClientCert != "" || config.TLSClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(config.TLSClientCert), []byte(config.TLSClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// legacyRoots returns the system roots along with the cluster-wide
+// certificate, or nil when it is not used. The certificate is ignored when it
+// does not parse, like the placeholder of the shipped tls-secret, so that
+// plain connections keep working.
+func (config Config) legacyRoots(password string) *x509.CertPool {
+	if config.TLSCertificate == "" || (config.LegacyTLSRequiresPassword && password == "") {
+		return nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if ok := roots.AppendCertsFromPEM([]byte(config.TLSCertificate)); !ok {
+		return nil
+	}
+	return roots
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// placeholderCert is the certificate of the shipped tls-secret.
+const placeholderCert = "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"
+
+func TestNewPool(t *testing.T) {
+	tests := map[string]struct {
+		config  Config
+		wantErr bool
+	}{
+		"address": {
+			config: Config{Address: "redis://redis:6379/1"},
+		},
+		"invalid address": {
+			config:  Config{Address: "redis://redis:6379/db"},
+			wantErr: true,
+		},
+		"placeholder certificate": {
+			config: Config{Address: "redis://redis:6379", TLSCertificate: placeholderCert},
+		},
+		"invalid CA certificate": {
+			config:  Config{Address: "rediss://redis:6379", TLSCACert: "not a certificate"},
+			wantErr: true,
+		},
+		"sentinel without address": {
+			config: Config{SentinelMasterName: "mymaster", SentinelAddresses: []string{"sentinel:26379"}},
+		},
+		"missing address": {
+			config:  Config{},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			_, err := NewPool(tc.config)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	cert := testCert(t)
+
+	tests := map[string]struct {
+		config     Config
+		rediss     bool
+		password   string
+		wantTLS    bool
+		wantVerify bool
+		wantErr    bool
+	}{
+		"plain": {},
+		"legacy certificate": {
+			config:     Config{TLSCertificate: cert},
+			wantTLS:    true,
+			wantVerify: true,
+		},
+		"placeholder legacy certificate": {
+			config: Config{TLSCertificate: placeholderCert},
+		},
+		"invalid legacy certificate": {
+			config: Config{TLSCertificate: "not a certificate"},
+		},
+		"legacy certificate without password": {
+			config: Config{TLSCertificate: cert, LegacyTLSRequiresPassword: true},
+		},
+		"legacy certificate with password": {
+			config:     Config{TLSCertificate: cert, LegacyTLSRequiresPassword: true},
+			password:   "secret",
+			wantTLS:    true,
+			wantVerify: true,
+		},
+		"rediss": {
+			rediss:     true,
+			wantTLS:    true,
+			wantVerify: true,
+		},
+		"use TLS": {
+			config:  Config{TLSEnabled: true, TLSSkipVerify: true},
+			wantTLS: true,
+		},
+		"CA certificate": {
+			config:     Config{TLSEnabled: true, TLSCACert: cert},
+			wantTLS:    true,
+			wantVerify: true,
+		},
+		"CA certificate without verification": {
+			config:  Config{TLSEnabled: true, TLSCACert: cert, TLSSkipVerify: true},
+			wantTLS: true,
+		},
+		"legacy certificate with use TLS": {
+			config:     Config{TLSEnabled: true, TLSCertificate: cert},
+			wantTLS:    true,
+			wantVerify: true,
+		},
+		"invalid CA certificate": {
+			config:  Config{TLSEnabled: true, TLSCACert: "not a certificate"},
+			wantErr: true,
+		},
+		"invalid client certificate": {
+			config:  Config{TLSClientCert: "not a certificate", TLSClientKey: "not a key"},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := tc.config.tlsConfig(tc.rediss, tc.password)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got != nil) != tc.wantTLS {
+				t.Errorf("got TLS config %v, want TLS %v", got, tc.wantTLS)
+			}
+			if got != nil && got.InsecureSkipVerify == tc.wantVerify {
+				t.Errorf("got InsecureSkipVerify %v, want verify %v", got.InsecureSkipVerify, tc.wantVerify)
+			}
+		})
+	}
+}
+
+func TestTLSVerify(t *testing.T) {
+	cert, key := testCertKey(t)
+	addr := serveTLS(t, cert, key)
+
+	tests := map[string]struct {
+		config  Config
+		wantErr bool
+	}{
+		"self-signed without CA certificate": {
+			config:  Config{Address: "rediss://" + addr},
+			wantErr: true,
+		},
+		"self-signed with CA certificate": {
+			config: Config{Address: "rediss://" + addr, TLSCACert: cert},
+		},
+		"self-signed with legacy certificate": {
+			config: Config{Address: "rediss://" + addr, TLSCertificate: cert},
+		},
+		"self-signed without verification": {
+			config: Config{Address: "rediss://" + addr, TLSSkipVerify: true},
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			pool, err := NewPool(tc.config)
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			defer pool.Close()
+
+			conn := pool.Get()
+			defer conn.Close()
+			_, err = conn.Do("PING")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var verifyErr *tls.CertificateVerificationError
+			if err != nil && !errors.As(err, &verifyErr) {
+				t.Errorf("got error %v, want a certificate verification error", err)
+			}
+		})
+	}
+}
+
+// serveTLS starts a server answering PONG to every command over TLS, with
+// the certificate and key in PEM, and returns its address.
+func serveTLS(t *testing.T, cert, key string) string {
+	t.Helper()
+	pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{pair}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					// PING is sent as an array of one bulk string.
+					for i := 0; i < 3; i++ {
+						if _, err := r.ReadString('\n'); err != nil {
+							return
+						}
+					}
+					if _, err := conn.Write([]byte("+PONG\r\n")); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// testCert returns a self-signed certificate in PEM.
+func testCert(t *testing.T) string {
+	t.Helper()
+	cert, _ := testCertKey(t)
+	return cert
+}
+
+// testCertKey returns a self-signed certificate for 127.0.0.1 and its key,
+// in PEM.
+func testCertKey(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestMasterConn(t *testing.T) {
+	fake := &fakeConn{}
+	conn := &masterConn{Conn: fake}
+
+	fake.err = redis.Error("ERR no such key")
+	conn.Do("XINFO", "GROUPS", "mystream")
+	if err := conn.Err(); err != nil {
+		t.Fatalf("connection marked as broken after %v", err)
+	}
+
+	fake.err = redis.Error("READONLY You can't write against a read only replica.")
+	conn.Do("XADD", "mystream", "*", "field", "value")
+	if err := conn.Err(); err == nil {
+		t.Fatal("connection not marked as broken after being demoted")
+	}
+}
+
+type fakeConn struct {
+	redis.Conn
+	err error
+}
+
+func (c *fakeConn) Do(string, ...interface{}) (interface{}, error) {
+	return nil, c.err
+}
+
+func (c *fakeConn) Err() error {
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+// ConfigFor returns the configuration connecting to the Redis instance of a
+// resource from the control plane. It reads the secrets referenced by conn
+// in the namespace of the resource, where the data plane gets them from its
+// environment.
+func ConfigFor(ctx context.Context, kubeClient kubernetes.Interface, namespace string, conn apisv1alpha1.RedisConnection, tlsCert string) (Config, error) {
+	config := Config{
+		Address:        conn.Address,
+		TLSCertificate: tlsCert,
+	}
+	secrets := secretReader{ctx: ctx, kubeClient: kubeClient, namespace: namespace}
+
+	var err error
+	if options := conn.Options; options != nil {
+		config.TLSEnabled = options.UseTLS
+		config.TLSSkipVerify = options.SkipVerify
+		if config.Password, err = secrets.value(options.PasswordSecretKeyRef()); err != nil {
+			return Config{}, err
+		}
+		if config.TLSClientCert, err = secrets.value(options.Cert.SecretKeyRef); err != nil {
+			return Config{}, err
+		}
+		if config.TLSClientKey, err = secrets.value(options.Key.SecretKeyRef); err != nil {
+			return Config{}, err
+		}
+		if config.TLSCACert, err = secrets.value(options.CACert.SecretKeyRef); err != nil {
+			return Config{}, err
+		}
+	}
+	if sentinel := conn.Sentinel; sentinel != nil {
+		config.SentinelMasterName = sentinel.MasterName
+		config.SentinelAddresses = sentinel.Addresses
+		if sentinel.Password != nil {
+			if config.SentinelPassword, err = secrets.value(sentinel.Password.SecretKeyRef); err != nil {
+				return Config{}, err
+			}
+		}
+	}
+	if cluster := conn.Cluster; cluster != nil {
+		config.ClusterMode = true
+		config.ClusterAddresses = cluster.Addresses
+	}
+	return config, nil
+}
+
+type secretReader struct {
+	ctx        context.Context
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// value returns the value of the secret key ref, or an empty string when ref
+// is nil or references a missing optional secret or key.
+func (r secretReader) value(ref *corev1.SecretKeySelector) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+	optional := ref.Optional != nil && *ref.Optional
+
+	secret, err := r.kubeClient.CoreV1().Secrets(r.namespace).Get(r.ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && optional {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", r.namespace, ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("secret %s/%s has no key %q", r.namespace, ref.Name, ref.Key)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestConfigFor(t *testing.T) {
+	optional := true
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "redis"},
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+			"ca":       []byte("ca cert"),
+		},
+	}
+	ref := func(name, key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+	}
+
+	tests := map[string]struct {
+		conn    apisv1alpha1.RedisConnection
+		want    Config
+		wantErr bool
+	}{
+		"address only": {
+			conn: apisv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			want: Config{Address: "redis://redis:6379", TLSCertificate: "cert"},
+		},
+		"dial options": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "redis://redis:6379",
+				Options: &apisv1alpha1.RedisConnectionOptions{
+					Password: corev1.ObjectReference{Name: "redis"},
+					UseTLS:   true,
+					CACert:   apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: ref("redis", "ca")},
+				},
+			},
+			want: Config{
+				Address:        "redis://redis:6379",
+				TLSCertificate: "cert",
+				Password:       "secret",
+				TLSEnabled:     true,
+				TLSCACert:      "ca cert",
+			},
+		},
+		"sentinel and cluster": {
+			conn: apisv1alpha1.RedisConnection{
+				Sentinel: &apisv1alpha1.RedisSentinel{
+					MasterName: "mymaster",
+					Addresses:  []string{"sentinel:26379"},
+					Password:   &apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: ref("redis", "password")},
+				},
+				Cluster: &apisv1alpha1.RedisCluster{Addresses: []string{"node:6379"}},
+			},
+			want: Config{
+				TLSCertificate:     "cert",
+				SentinelMasterName: "mymaster",
+				SentinelAddresses:  []string{"sentinel:26379"},
+				SentinelPassword:   "secret",
+				ClusterMode:        true,
+				ClusterAddresses:   []string{"node:6379"},
+			},
+		},
+		"missing optional secret": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "redis://redis:6379",
+				Options: &apisv1alpha1.RedisConnectionOptions{
+					Cert: apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+						Key:                  "cert",
+						Optional:             &optional,
+					}},
+				},
+			},
+			want: Config{Address: "redis://redis:6379", TLSCertificate: "cert"},
+		},
+		"missing secret": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "redis://redis:6379",
+				Options: &apisv1alpha1.RedisConnectionOptions{
+					Password: corev1.ObjectReference{Name: "missing"},
+				},
+			},
+			wantErr: true,
+		},
+		"missing key": {
+			conn: apisv1alpha1.RedisConnection{
+				Address: "redis://redis:6379",
+				Options: &apisv1alpha1.RedisConnectionOptions{
+					Key: apisv1alpha1.RedisSecretValueFromSource{SecretKeyRef: ref("redis", "key")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(secret)
+			got, err := ConfigFor(context.Background(), kubeClient, "ns", tc.conn, "cert")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// sentinelTimeout bounds the time spent asking a sentinel for the master.
+const sentinelTimeout = 5 * time.Second
+
+// sentinel discovers the current master through Redis Sentinel.
+type sentinel struct {
+	masterName string
+	addresses  []string
+	password   string
+
+	// options used to dial the master
+	options []redis.DialOption
+}
+
+// dialMaster connects to the current master.
+func (s *sentinel) dialMaster() (redis.Conn, error) {
+	addr, err := s.masterAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := redis.Dial("tcp", addr, s.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The sentinels may report a master that has just been demoted.
+	role, err := redis.Values(c.Do("ROLE"))
+	if err == nil && len(role) == 0 {
+		err = errors.New("empty ROLE reply")
+	}
+	if err == nil {
+		var name string
+		if name, err = redis.String(role[0], nil); err == nil && name != "master" {
+			err = fmt.Errorf("%s is a %s, not a master", addr, name)
+		}
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return &masterConn{Conn: c}, nil
+}
+
+// masterAddr returns the address of the master as reported by the first
+// sentinel that answers.
+func (s *sentinel) masterAddr() (string, error) {
+	if len(s.addresses) == 0 {
+		return "", errors.New("no sentinel addresses")
+	}
+	var errs []string
+	for _, addr := range s.addresses {
+		master, err := s.queryMaster(addr)
+		if err == nil {
+			return master, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", addr, err))
+	}
+	return "", fmt.Errorf("cannot get address of master %q: %s", s.masterName, strings.Join(errs, "; "))
+}
+
+func (s *sentinel) queryMaster(addr string) (string, error) {
+	c, err := redis.Dial("tcp", addr,
+		redis.DialPassword(s.password),
+		redis.DialConnectTimeout(sentinelTimeout),
+		redis.DialReadTimeout(sentinelTimeout),
+		redis.DialWriteTimeout(sentinelTimeout),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	hostPort, err := redis.Strings(c.Do("SENTINEL", "get-master-addr-by-name", s.masterName))
+	if err != nil {
+		return "", err
+	}
+	if len(hostPort) != 2 {
+		return "", fmt.Errorf("unexpected reply %v", hostPort)
+	}
+	return net.JoinHostPort(hostPort[0], hostPort[1]), nil
+}
+
+// masterConn is a connection to a master. It becomes unusable once the master
+// has been demoted to a replica by a failover, so that the pool discards it and
+// callers know to reconnect.
+type masterConn struct {
+	redis.Conn
+	err error
+}
+
+func (c *masterConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	reply, err := c.Conn.Do(commandName, args...)
+	c.check(err)
+	return reply, err
+}
+
+func (c *masterConn) Receive() (interface{}, error) {
+	reply, err := c.Conn.Receive()
+	c.check(err)
+	return reply, err
+}
+
+func (c *masterConn) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Conn.Err()
+}
+
+func (c *masterConn) check(err error) {
+	if err != nil && strings.HasPrefix(err.Error(), "READONLY") {
+		c.err = err
+	}
+}
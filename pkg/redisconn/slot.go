@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// slotCount is the number of hash slots of a Redis Cluster.
+const slotCount = 16384
+
+// keySlot returns the hash slot of key. Only the hash tag, the part between
+// the first { and the following }, is hashed when it is not empty.
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key)) % slotCount
+}
+
+// SameSlot tells whether the keys hash to the same slot of a Redis Cluster,
+// which is required for commands operating on several keys at once.
+func SameSlot(keys ...string) bool {
+	for i := 1; i < len(keys); i++ {
+		if keySlot(keys[i]) != keySlot(keys[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// crc16 implements the CRC16-CCITT (XMODEM) checksum used by Redis Cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// commandKey returns the key a command operates on, used to route it to the
+// node owning the key slot. Commands operating on several keys, such as
+// XREADGROUP on several streams, are routed by their first key, the keys
+// must share its slot.
+func commandKey(commandName string, args []interface{}) (string, bool) {
+	switch strings.ToUpper(commandName) {
+	case "XREAD", "XREADGROUP":
+		for i := 0; i < len(args)-1; i++ {
+			if strings.EqualFold(argString(args[i]), "STREAMS") {
+				return argString(args[i+1]), true
+			}
+		}
+		return "", false
+	case "XINFO", "XGROUP":
+		if len(args) < 2 {
+			return "", false
+		}
+		return argString(args[1]), true
+	case "EVAL", "EVALSHA":
+		// The script keys follow the script and the number of keys.
+		if len(args) < 3 || fmt.Sprint(args[1]) == "0" {
+			return "", false
+		}
+		return argString(args[2]), true
+	case "", "PING", "ROLE", "INFO", "CLUSTER", "ASKING", "READONLY", "AUTH", "SELECT":
+		return "", false
+	default:
+		if len(args) == 0 {
+			return "", false
+		}
+		return argString(args[0]), true
+	}
+}
+
+func argString(arg interface{}) string {
+	switch arg := arg.(type) {
+	case string:
+		return arg
+	case []byte:
+		return string(arg)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redisconn
+
+import "testing"
+
+func TestKeySlot(t *testing.T) {
+	tests := map[string]int{
+		"123456789":            12739,
+		"foo":                  12182,
+		"{user1000}.following": keySlot("user1000"),
+		"{user1000}.followers": keySlot("user1000"),
+		"foo{}{bar}":           keySlot("foo{}{bar}"),
+		"foo{{bar}}zap":        keySlot("{bar"),
+	}
+	for key, want := range tests {
+		if got := keySlot(key); got != want {
+			t.Errorf("keySlot(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestSameSlot(t *testing.T) {
+	if !SameSlot() || !SameSlot("orders") {
+		t.Error("expected no or a single key to share a slot")
+	}
+	if !SameSlot("{orders}:acme", "{orders}:globex") {
+		t.Error("expected keys with the same hash tag to share a slot")
+	}
+	if SameSlot("orders:acme", "orders:globex") {
+		t.Error("expected keys without hash tag to hash to different slots")
+	}
+}
+
+func TestCommandKey(t *testing.T) {
+	tests := []struct {
+		cmd    string
+		args   []interface{}
+		want   string
+		wantOK bool
+	}{
+		{cmd: "XADD", args: []interface{}{"mystream", "*", "f", "v"}, want: "mystream", wantOK: true},
+		{cmd: "XACK", args: []interface{}{[]byte("mystream"), "mygroup", "1-0"}, want: "mystream", wantOK: true},
+		{cmd: "XGROUP", args: []interface{}{"CREATE", "mystream", "mygroup", "$"}, want: "mystream", wantOK: true},
+		{cmd: "XINFO", args: []interface{}{"GROUPS", "mystream"}, want: "mystream", wantOK: true},
+		{cmd: "XREADGROUP", args: []interface{}{"GROUP", "g", "c", "COUNT", 1, "BLOCK", 5000, "STREAMS", "mystream", ">"}, want: "mystream", wantOK: true},
+		{cmd: "XREADGROUP", args: []interface{}{"GROUP", "g", "c"}},
+		{cmd: "EVALSHA", args: []interface{}{"sha", 2, "{mylist}:processing:c", "mylist", "c"}, want: "{mylist}:processing:c", wantOK: true},
+		{cmd: "EVAL", args: []interface{}{"return 1", 0}},
+		{cmd: "PING"},
+		{cmd: "CLUSTER", args: []interface{}{"SLOTS"}},
+	}
+	for _, tc := range tests {
+		got, ok := commandKey(tc.cmd, tc.args)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("commandKey(%s, %v) = %q, %v, want %q, %v", tc.cmd, tc.args, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -45,10 +45,10 @@ type RedisStreamSink struct {
 var (
 	_ runtime.Object     = (*RedisStreamSink)(nil)
 	_ kmeta.OwnerRefable = (*RedisStreamSink)(nil)
-	//_ apis.Validatable   = (*RedisStreamSink)(nil)
-	//_ apis.Defaultable   = (*RedisStreamSink)(nil)
-	_ apis.HasSpec    = (*RedisStreamSink)(nil)
-	_ duckv1.KRShaped = (*RedisStreamSink)(nil)
+	_ apis.Validatable   = (*RedisStreamSink)(nil)
+	_ apis.Defaultable   = (*RedisStreamSink)(nil)
+	_ apis.HasSpec       = (*RedisStreamSink)(nil)
+	_ duckv1.KRShaped    = (*RedisStreamSink)(nil)
 )
 
 // RedisStreamSinkSpec defines the desired state of the RedisStreamSink.
@@ -57,10 +57,120 @@ type RedisStreamSinkSpec struct {
 	// to a Redis instance
 	apisv1alpha1.RedisConnection `json:",inline"`
 
-	// Stream is the name of the stream to send events to
+	// Stream is the name of the stream to send events to. Exactly one of the
+	// stream, the publish spec and the list spec must be set.
 	Stream string `json:"stream"`
+
+	// Publish publishes the events on a Pub/Sub channel instead of adding
+	// them to a stream.
+	// +optional
+	Publish *PublishSpec `json:"publish,omitempty"`
+
+	// List pushes the events on a list instead of adding them to a stream.
+	// +optional
+	List *ListSpec `json:"list,omitempty"`
+
+	// Encoding is how events are stored in the stream entries, either
+	// "array", "fields" or "structured". Defaults to "array".
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// Trim bounds the stream, which is trimmed by every XADD. The stream
+	// grows without limit when not set.
+	// +optional
+	Trim *TrimSpec `json:"trim,omitempty"`
+}
+
+// TrimSpec defines how the stream is trimmed. Exactly one of MaxLen and MaxAge
+// must be set.
+type TrimSpec struct {
+	// MaxLen is the maximum number of entries kept in the stream (MAXLEN).
+	// +optional
+	MaxLen *int64 `json:"maxLen,omitempty"`
+
+	// MaxAge is the maximum age of the entries kept in the stream, as an
+	// ISO-8601 duration. Older entries are evicted based on their ID (MINID).
+	// +optional
+	MaxAge *string `json:"maxAge,omitempty"`
+
+	// Approximate trims the stream only when whole nodes can be removed (~),
+	// which is much more efficient. The stream may then hold a few more
+	// entries than requested. Defaults to false.
+	// +optional
+	Approximate *bool `json:"approximate,omitempty"`
+
+	// Limit is the maximum number of entries evicted by each XADD (LIMIT).
+	// Only valid with approximate trimming.
+	// +optional
+	Limit *int64 `json:"limit,omitempty"`
+}
+
+// PublishSpec defines how events are published on a Pub/Sub channel.
+type PublishSpec struct {
+	// Channel is the channel the events are published on. It is a Go
+	// template executed with the event attributes and extensions, for
+	// instance "orders.{{.type}}".
+	Channel string `json:"channel"`
+
+	// Encoding is how events are published, either "structured" or "data".
+	// Defaults to "structured".
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// ListSpec defines how events are pushed on a list.
+type ListSpec struct {
+	// Name is the name of the list.
+	Name string `json:"name"`
+
+	// Push is the end of the list the events are pushed on, either "left"
+	// (LPUSH) or "right" (RPUSH). Defaults to "left".
+	// +optional
+	Push string `json:"push,omitempty"`
+
+	// MaxLen is the maximum number of items kept in the list. The oldest
+	// items are trimmed (LTRIM) after each push. The list grows without limit
+	// when not set.
+	// +optional
+	MaxLen *int64 `json:"maxLen,omitempty"`
+
+	// Encoding is how events are stored in the list items, either
+	// "structured" or "data". Defaults to "structured".
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
 }
 
+const (
+	// PushLeft pushes the events on the head of the list (LPUSH).
+	PushLeft = "left"
+
+	// PushRight pushes the events on the tail of the list (RPUSH).
+	PushRight = "right"
+)
+
+const (
+	// PayloadEncodingStructured publishes or pushes the whole event in
+	// structured mode JSON.
+	PayloadEncodingStructured = "structured"
+
+	// PayloadEncodingData publishes or pushes the event data only.
+	PayloadEncodingData = "data"
+)
+
+const (
+	// EncodingArray stores the event data, a JSON array of alternating field
+	// and value items, as the entry. Event attributes are not stored.
+	EncodingArray = "array"
+
+	// EncodingFields stores the event attributes and extensions as CE prefixed
+	// fields, and the event data as the data field.
+	EncodingFields = "fields"
+
+	// EncodingStructured stores the whole event in structured mode JSON as the
+	// cloudevent field.
+	EncodingStructured = "structured"
+)
+
 // RedisStreamSinkStatus defines the observed state of RedisStreamSink.
 type RedisStreamSinkStatus struct {
 	// inherits duck/v1 Status, which currently provides:
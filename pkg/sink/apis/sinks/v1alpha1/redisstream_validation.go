@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"text/template"
+
+	"knative.dev/pkg/apis"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+// Validate implements apis.Validatable.
+func (s *RedisStreamSink) Validate(ctx context.Context) *apis.FieldError {
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate checks the connection and that events go to exactly one of a
+// stream, a Pub/Sub channel and a list.
+func (s *RedisStreamSinkSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := s.RedisConnection.Validate(ctx)
+
+	targets := 0
+	for _, set := range []bool{s.Stream != "", s.Publish != nil, s.List != nil} {
+		if set {
+			targets++
+		}
+	}
+	switch targets {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("stream", "publish", "list"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf("stream", "publish", "list"))
+	}
+
+	switch s.Encoding {
+	case "", EncodingArray, EncodingFields, EncodingStructured:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.Encoding, "encoding"))
+	}
+
+	errs = errs.Also(s.Trim.Validate(ctx).ViaField("trim"))
+	errs = errs.Also(s.Publish.Validate(ctx).ViaField("publish"))
+	return errs.Also(s.List.Validate(ctx).ViaField("list"))
+}
+
+// Validate checks that exactly one of the maximum length and age is set, and
+// that the limit only comes with approximate trimming.
+func (t *TrimSpec) Validate(ctx context.Context) *apis.FieldError {
+	if t == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	switch {
+	case t.MaxLen == nil && t.MaxAge == nil:
+		errs = errs.Also(apis.ErrMissingOneOf("maxLen", "maxAge"))
+	case t.MaxLen != nil && t.MaxAge != nil:
+		errs = errs.Also(apis.ErrMultipleOneOf("maxLen", "maxAge"))
+	}
+	if t.MaxLen != nil && *t.MaxLen < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*t.MaxLen, "maxLen", "expected a positive number or zero"))
+	}
+	if t.MaxAge != nil {
+		errs = errs.Also(apisv1alpha1.ValidateDuration(*t.MaxAge, "maxAge"))
+	}
+	if t.Limit != nil {
+		if *t.Limit < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(*t.Limit, "limit", "expected a positive number or zero"))
+		}
+		if t.Approximate == nil || !*t.Approximate {
+			errs = errs.Also(apis.ErrGeneric("limit requires approximate trimming", "limit", "approximate"))
+		}
+	}
+	return errs
+}
+
+// Validate checks that the channel template parses.
+func (p *PublishSpec) Validate(ctx context.Context) *apis.FieldError {
+	if p == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if p.Channel == "" {
+		errs = errs.Also(apis.ErrMissingField("channel"))
+	} else if _, err := template.New("channel").Parse(p.Channel); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(p.Channel, "channel", err.Error()))
+	}
+	return errs.Also(validatePayloadEncoding(p.Encoding))
+}
+
+// Validate checks that the list is named and the push end is known.
+func (l *ListSpec) Validate(ctx context.Context) *apis.FieldError {
+	if l == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if l.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+	switch l.Push {
+	case "", PushLeft, PushRight:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(l.Push, "push"))
+	}
+	if l.MaxLen != nil && *l.MaxLen < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*l.MaxLen, "maxLen", "expected a positive number"))
+	}
+	return errs.Also(validatePayloadEncoding(l.Encoding))
+}
+
+func validatePayloadEncoding(encoding string) *apis.FieldError {
+	switch encoding {
+	case "", PayloadEncodingStructured, PayloadEncodingData:
+		return nil
+	default:
+		return apis.ErrInvalidValue(encoding, "encoding")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestRedisStreamSink_Validate(t *testing.T) {
+	spec := func(mutate func(*RedisStreamSinkSpec)) RedisStreamSinkSpec {
+		s := RedisStreamSinkSpec{
+			RedisConnection: apisv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:          "orders",
+		}
+		mutate(&s)
+		return s
+	}
+
+	tests := []struct {
+		name string
+		spec RedisStreamSinkSpec
+		want *apis.FieldError
+	}{{
+		name: "stream",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Encoding = EncodingStructured
+			s.Trim = &TrimSpec{MaxLen: ptr.Int64(1000), Approximate: ptr.Bool(true), Limit: ptr.Int64(100)}
+		}),
+	}, {
+		name: "publish",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Stream = ""
+			s.Publish = &PublishSpec{Channel: "orders.{{.type}}", Encoding: PayloadEncodingData}
+		}),
+	}, {
+		name: "list",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Stream = ""
+			s.List = &ListSpec{Name: "jobs", Push: PushRight, MaxLen: ptr.Int64(100)}
+		}),
+	}, {
+		name: "missing stream",
+		spec: spec(func(s *RedisStreamSinkSpec) { s.Stream = "" }),
+		want: apis.ErrMissingOneOf("spec.stream", "spec.publish", "spec.list"),
+	}, {
+		name: "stream and list",
+		spec: spec(func(s *RedisStreamSinkSpec) { s.List = &ListSpec{Name: "jobs"} }),
+		want: apis.ErrMultipleOneOf("spec.stream", "spec.publish", "spec.list"),
+	}, {
+		name: "missing address",
+		spec: spec(func(s *RedisStreamSinkSpec) { s.Address = "" }),
+		want: apis.ErrMissingField("spec.address"),
+	}, {
+		name: "invalid encoding",
+		spec: spec(func(s *RedisStreamSinkSpec) { s.Encoding = "json" }),
+		want: apis.ErrInvalidValue("json", "spec.encoding"),
+	}, {
+		name: "invalid trim",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Trim = &TrimSpec{MaxLen: ptr.Int64(1000), MaxAge: ptr.String("1h"), Limit: ptr.Int64(100)}
+		}),
+		want: apis.ErrMultipleOneOf("spec.trim.maxLen", "spec.trim.maxAge").
+			Also(apis.ErrInvalidValue("1h", "spec.trim.maxAge", "expected an ISO-8601 duration")).
+			Also(apis.ErrGeneric("limit requires approximate trimming", "spec.trim.limit", "spec.trim.approximate")),
+	}, {
+		name: "invalid publish",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Stream = ""
+			s.Publish = &PublishSpec{Encoding: "binary"}
+		}),
+		want: apis.ErrMissingField("spec.publish.channel").
+			Also(apis.ErrInvalidValue("binary", "spec.publish.encoding")),
+	}, {
+		name: "invalid list",
+		spec: spec(func(s *RedisStreamSinkSpec) {
+			s.Stream = ""
+			s.List = &ListSpec{Push: "middle", MaxLen: ptr.Int64(0)}
+		}),
+		want: apis.ErrMissingField("spec.list.name").
+			Also(apis.ErrInvalidValue("middle", "spec.list.push")).
+			Also(apis.ErrInvalidValue(0, "spec.list.maxLen", "expected a positive number")),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &RedisStreamSink{Spec: tt.spec}
+			got := sink.Validate(context.Background())
+			if diff := cmp.Diff(tt.want.Error(), got.Error()); diff != "" {
+				t.Error("Unexpected validation error (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestPublishSpec_ValidateChannel(t *testing.T) {
+	publish := &PublishSpec{Channel: "orders.{{.type"}
+	if err := publish.Validate(context.Background()); err == nil {
+		t.Error("Expected an error for an unterminated channel template")
+	}
+}
@@ -25,6 +25,43 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListSpec) DeepCopyInto(out *ListSpec) {
+	*out = *in
+	if in.MaxLen != nil {
+		in, out := &in.MaxLen, &out.MaxLen
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListSpec.
+func (in *ListSpec) DeepCopy() *ListSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ListSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishSpec) DeepCopyInto(out *PublishSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishSpec.
+func (in *PublishSpec) DeepCopy() *PublishSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisStreamSink) DeepCopyInto(out *RedisStreamSink) {
 	*out = *in
@@ -90,6 +127,21 @@ func (in *RedisStreamSinkList) DeepCopyObject() runtime.Object {
 func (in *RedisStreamSinkSpec) DeepCopyInto(out *RedisStreamSinkSpec) {
 	*out = *in
 	in.RedisConnection.DeepCopyInto(&out.RedisConnection)
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = new(PublishSpec)
+		**out = **in
+	}
+	if in.List != nil {
+		in, out := &in.List, &out.List
+		*out = new(ListSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trim != nil {
+		in, out := &in.Trim, &out.Trim
+		*out = new(TrimSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -120,3 +172,39 @@ func (in *RedisStreamSinkStatus) DeepCopy() *RedisStreamSinkStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrimSpec) DeepCopyInto(out *TrimSpec) {
+	*out = *in
+	if in.MaxLen != nil {
+		in, out := &in.MaxLen, &out.MaxLen
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(string)
+		**out = **in
+	}
+	if in.Approximate != nil {
+		in, out := &in.Approximate, &out.Approximate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrimSpec.
+func (in *TrimSpec) DeepCopy() *TrimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,12 +17,33 @@ package receiver
 
 import (
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
 )
 
 type Config struct {
 	adapter.EnvConfig
+	redisconn.Config
+
+	Stream   string `envconfig:"STREAM" required:"true"`
+	Encoding string `envconfig:"ENCODING"`
+
+	// Trim options. The stream is not trimmed when neither the maximum
+	// length nor the maximum age, an ISO-8601 duration, is set.
+	TrimMaxLen      string `envconfig:"TRIM_MAXLEN"`
+	TrimMaxAge      string `envconfig:"TRIM_MAX_AGE"`
+	TrimApproximate string `envconfig:"TRIM_APPROXIMATE"`
+	TrimLimit       string `envconfig:"TRIM_LIMIT"`
+
+	// Publish options. Events are published on the channel named by the
+	// template instead of being added to the stream when it is set.
+	PublishChannel  string `envconfig:"PUBLISH_CHANNEL"`
+	PublishEncoding string `envconfig:"PUBLISH_ENCODING"`
 
-	Address        string `envconfig:"ADDRESS" required:"true"`
-	Stream         string `envconfig:"STREAM" required:"true"`
-	TLSCertificate string `envconfig:"TLS_CERTIFICATE" required:"true"`
+	// List options. Events are pushed on the list instead of being added to
+	// the stream when its name is set.
+	ListName     string `envconfig:"LIST_NAME"`
+	ListPush     string `envconfig:"LIST_PUSH"`
+	ListMaxLen   string `envconfig:"LIST_MAX_LEN"`
+	ListEncoding string `envconfig:"LIST_ENCODING"`
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+
+	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
+)
+
+// pusher pushes events on a list, optionally capping its length.
+type pusher struct {
+	list     string
+	command  string
+	maxLen   int64
+	encoding string
+}
+
+// parseList returns the pusher configured by config, or nil when events are
+// added to the stream.
+func parseList(config *Config) (*pusher, error) {
+	if config.ListName == "" {
+		return nil, nil
+	}
+
+	p := &pusher{list: config.ListName, command: "LPUSH"}
+	switch config.ListPush {
+	case "", sinksv1alpha1.PushLeft:
+	case sinksv1alpha1.PushRight:
+		p.command = "RPUSH"
+	default:
+		return nil, fmt.Errorf("invalid list push %q", config.ListPush)
+	}
+
+	if config.ListMaxLen != "" {
+		maxLen, err := strconv.ParseInt(config.ListMaxLen, 10, 64)
+		if err != nil || maxLen < 1 {
+			return nil, fmt.Errorf("invalid list max length %q", config.ListMaxLen)
+		}
+		p.maxLen = maxLen
+	}
+
+	encoding, err := parsePayloadEncoding(config.ListEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list encoding: %w", err)
+	}
+	p.encoding = encoding
+	return p, nil
+}
+
+// trimArgs returns the LTRIM arguments keeping the most recently pushed items.
+func (p *pusher) trimArgs() []interface{} {
+	if p.command == "RPUSH" {
+		return []interface{}{p.list, -p.maxLen, -1}
+	}
+	return []interface{}{p.list, 0, p.maxLen - 1}
+}
+
+// push pushes event on the list, then trims the list when it is too long.
+func (r *receiver) push(event cloudevents.Event) protocol.Result {
+	value, err := encodePayload(r.pusher.encoding, event)
+	if err != nil {
+		r.logger.Error("Cannot encode event", zap.Error(err))
+		return cehttp.NewResult(http.StatusBadRequest, "cannot encode event: %v", err)
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	start := time.Now()
+	length, err := redis.Int64(conn.Do(r.pusher.command, r.pusher.list, value))
+	r.recordWrite(r.pusher.command, start)
+	if err != nil {
+		r.logger.Error("Cannot push to list", zap.Error(err))
+		return writeResult(err, "cannot push to list")
+	}
+	if r.pusher.maxLen > 0 && length > r.pusher.maxLen {
+		if _, err := conn.Do("LTRIM", r.pusher.trimArgs()...); err != nil {
+			// The event was pushed, the list is trimmed by the next push.
+			r.logger.Error("Cannot trim list", zap.Error(err))
+		}
+	}
+	r.logger.Info("Pushed event to the list")
+	return protocol.ResultACK
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func TestParseList(t *testing.T) {
+	tests := map[string]struct {
+		config      Config
+		wantCommand string
+		wantTrim    []interface{}
+		wantErr     bool
+	}{
+		"defaults": {
+			config:      Config{ListName: "queue:default"},
+			wantCommand: "LPUSH",
+			wantTrim:    []interface{}{"queue:default", 0, int64(-1)},
+		},
+		"push right with max length": {
+			config:      Config{ListName: "rq:queue:default", ListPush: "right", ListMaxLen: "100"},
+			wantCommand: "RPUSH",
+			wantTrim:    []interface{}{"rq:queue:default", int64(-100), -1},
+		},
+		"invalid push": {
+			config:  Config{ListName: "jobs", ListPush: "middle"},
+			wantErr: true,
+		},
+		"invalid max length": {
+			config:  Config{ListName: "jobs", ListMaxLen: "0"},
+			wantErr: true,
+		},
+		"invalid encoding": {
+			config:  Config{ListName: "jobs", ListEncoding: "fields"},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			p, err := parseList(&tc.config)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCommand, p.command)
+			require.Equal(t, tc.wantTrim, p.trimArgs())
+		})
+	}
+
+	p, err := parseList(&Config{})
+	require.NoError(t, err)
+	require.Nil(t, p, "should add events to the stream without list")
+}
+
+func TestReceiver_Push(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.job")
+	event.SetSource("/jobs")
+	event.SetData(cloudevents.ApplicationJSON, map[string]string{"class": "HardWorker"})
+
+	p, err := parseList(&Config{ListName: "queue:default", ListMaxLen: "2", ListEncoding: "data"})
+	require.NoError(t, err)
+
+	conn := &fakeConn{replies: map[string]interface{}{"LPUSH": int64(3), "LTRIM": "OK"}}
+	r := &receiver{
+		config:  &Config{},
+		logger:  zap.NewNop(),
+		pool:    &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+		pusher:  p,
+		metrics: newMetrics(noop.NewMeterProvider()),
+	}
+
+	require.True(t, protocol.IsACK(r.Receive(event)))
+	require.Equal(t, [][]interface{}{
+		{"LPUSH", "queue:default", []byte(`{"class":"HardWorker"}`)},
+		{"LTRIM", "queue:default", 0, int64(1)},
+	}, conn.commands)
+
+	// The list is only trimmed when too long
+	conn.commands = nil
+	conn.replies["LPUSH"] = int64(2)
+	require.True(t, protocol.IsACK(r.Receive(event)))
+	require.Len(t, conn.commands, 1)
+}
+
+// fakeConn is a redis connection returning canned replies per command.
+type fakeConn struct {
+	redis.Conn
+	replies  map[string]interface{}
+	commands [][]interface{}
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return nil }
+
+func (c *fakeConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if commandName == "" {
+		return nil, nil
+	}
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	return c.replies[commandName], nil
+}
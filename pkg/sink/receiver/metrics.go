@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const scopeName = "knative.dev/eventing-redis/pkg/sink/receiver"
+
+var (
+	NamespaceAttr = attributekey.String("k8s.namespace.name")
+	SinkNameAttr  = attributekey.String("kn.sink.name")
+	StreamAttr    = attributekey.String("redis.stream")
+	ListAttr      = attributekey.String("redis.list")
+	OperationAttr = attributekey.String("db.operation.name")
+	OutcomeAttr   = attributekey.String("kn.redis.outcome")
+)
+
+// The outcomes of the requests received by the sink.
+const (
+	OutcomeAccepted    = "accepted"
+	OutcomeRejected    = "rejected"
+	OutcomeUnavailable = "unavailable"
+	OutcomeFailed      = "failed"
+)
+
+type metrics struct {
+	requests      metric.Int64Counter
+	writeDuration metric.Float64Histogram
+}
+
+func newMetrics(provider metric.MeterProvider) *metrics {
+	var (
+		m   metrics
+		err error
+	)
+
+	meter := provider.Meter(scopeName)
+
+	m.requests, err = meter.Int64Counter(
+		"kn.redis.sink.requests",
+		metric.WithDescription("Number of events received by the sink, by outcome."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.writeDuration, err = meter.Float64Histogram(
+		"kn.redis.sink.write.duration",
+		metric.WithDescription("The duration of the Redis command writing an event."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return &m
+}
+
+// attributes returns the attributes of the measurements of the sink, along
+// with the given ones.
+func (r *receiver) attributes(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	base := []attribute.KeyValue{
+		NamespaceAttr.With(r.config.Namespace),
+		SinkNameAttr.With(r.config.Name),
+	}
+	switch {
+	case r.pusher != nil:
+		base = append(base, ListAttr.With(r.pusher.list))
+	case r.publisher == nil:
+		base = append(base, StreamAttr.With(r.config.Stream))
+	}
+	return metric.WithAttributes(append(base, attrs...)...)
+}
+
+// recordWrite records the duration of the Redis command started at start.
+func (r *receiver) recordWrite(operation string, start time.Time) {
+	r.metrics.writeDuration.Record(context.Background(), time.Since(start).Seconds(), r.attributes(OperationAttr.With(operation)))
+}
+
+// outcome returns the outcome of a request given its result.
+func outcome(result protocol.Result) string {
+	if protocol.IsACK(result) {
+		return OutcomeAccepted
+	}
+	var httpResult *cehttp.Result
+	if errors.As(result, &httpResult) {
+		switch httpResult.StatusCode {
+		case http.StatusBadRequest:
+			return OutcomeRejected
+		case http.StatusServiceUnavailable:
+			return OutcomeUnavailable
+		}
+	}
+	return OutcomeFailed
+}
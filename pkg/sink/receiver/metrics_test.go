@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+func TestOutcome(t *testing.T) {
+	for want, result := range map[string]protocol.Result{
+		OutcomeAccepted:    protocol.ResultACK,
+		OutcomeRejected:    cehttp.NewResult(http.StatusBadRequest, "cannot encode event"),
+		OutcomeUnavailable: cehttp.NewResult(http.StatusServiceUnavailable, "redis is unavailable"),
+		OutcomeFailed:      errors.New("boom"),
+	} {
+		require.Equal(t, want, outcome(result))
+	}
+}
+
+func TestReceiver_Metrics(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.job")
+	event.SetSource("/jobs")
+	event.SetData(cloudevents.ApplicationJSON, map[string]string{"class": "HardWorker"})
+
+	p, err := parseList(&Config{ListName: "queue:default"})
+	require.NoError(t, err)
+
+	conn := &fakeConn{replies: map[string]interface{}{"LPUSH": int64(1)}}
+	reader := sdkmetric.NewManualReader()
+	r := &receiver{
+		config:  &Config{EnvConfig: adapter.EnvConfig{Namespace: "ns", Name: "mysink"}},
+		logger:  zap.NewNop(),
+		pool:    &redis.Pool{Dial: func() (redis.Conn, error) { return conn, nil }},
+		pusher:  p,
+		metrics: newMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	}
+	require.True(t, protocol.IsACK(r.Receive(event)))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	base := []attribute.KeyValue{NamespaceAttr.With("ns"), SinkNameAttr.With("mysink"), ListAttr.With("queue:default")}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			require.Equal(t, "kn.redis.sink.requests", m.Name)
+			require.Len(t, data.DataPoints, 1)
+			require.Equal(t, int64(1), data.DataPoints[0].Value)
+			require.Equal(t, attribute.NewSet(append(base, OutcomeAttr.With(OutcomeAccepted))...), data.DataPoints[0].Attributes)
+		case metricdata.Histogram[float64]:
+			require.Equal(t, "kn.redis.sink.write.duration", m.Name)
+			require.Len(t, data.DataPoints, 1)
+			require.Equal(t, uint64(1), data.DataPoints[0].Count)
+			require.Equal(t, attribute.NewSet(append(base, OperationAttr.With("LPUSH"))...), data.DataPoints[0].Attributes)
+		default:
+			t.Fatalf("unexpected data for %s: %T", m.Name, m.Data)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+
+	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
+)
+
+// publisher publishes events on the Pub/Sub channel named after their attributes.
+type publisher struct {
+	channel  *template.Template
+	encoding string
+}
+
+// parsePublish returns the publisher configured by config, or nil when events
+// are added to the stream.
+func parsePublish(config *Config) (*publisher, error) {
+	if config.PublishChannel == "" {
+		return nil, nil
+	}
+
+	encoding, err := parsePayloadEncoding(config.PublishEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish encoding: %w", err)
+	}
+	channel, err := template.New("channel").Option("missingkey=error").Parse(config.PublishChannel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish channel: %w", err)
+	}
+	return &publisher{channel: channel, encoding: encoding}, nil
+}
+
+// channelFor returns the channel event is published on. It fails when the
+// template references an attribute the event does not have.
+func (p *publisher) channelFor(event cloudevents.Event) (string, error) {
+	var channel strings.Builder
+	if err := p.channel.Execute(&channel, attributes(event)); err != nil {
+		return "", err
+	}
+	if channel.Len() == 0 {
+		return "", fmt.Errorf("empty channel")
+	}
+	return channel.String(), nil
+}
+
+// payload returns the message published for event.
+func (p *publisher) payload(event cloudevents.Event) ([]byte, error) {
+	return encodePayload(p.encoding, event)
+}
+
+// parsePayloadEncoding returns the payload encoding, "structured" by default.
+func parsePayloadEncoding(value string) (string, error) {
+	switch value {
+	case "", sinksv1alpha1.PayloadEncodingStructured:
+		return sinksv1alpha1.PayloadEncodingStructured, nil
+	case sinksv1alpha1.PayloadEncodingData:
+		return sinksv1alpha1.PayloadEncodingData, nil
+	default:
+		return "", fmt.Errorf("unknown encoding %q", value)
+	}
+}
+
+// encodePayload returns the single value storing event in the encoding.
+func encodePayload(encoding string, event cloudevents.Event) ([]byte, error) {
+	if encoding == sinksv1alpha1.PayloadEncodingData {
+		return event.Data(), nil
+	}
+	return json.Marshal(event)
+}
+
+// attributes returns the string values of the attributes and extensions of
+// event, by name.
+func attributes(event cloudevents.Event) map[string]string {
+	attrs := map[string]string{
+		"specversion": event.SpecVersion(),
+		"id":          event.ID(),
+		"type":        event.Type(),
+		"source":      event.Source(),
+	}
+	if subject := event.Subject(); subject != "" {
+		attrs["subject"] = subject
+	}
+	if contentType := event.DataContentType(); contentType != "" {
+		attrs["datacontenttype"] = contentType
+	}
+	if schema := event.DataSchema(); schema != "" {
+		attrs["dataschema"] = schema
+	}
+	if !event.Time().IsZero() {
+		attrs["time"] = types.FormatTime(event.Time())
+	}
+	for name, value := range event.Extensions() {
+		if s, err := types.Format(value); err == nil {
+			attrs[name] = s
+		}
+	}
+	return attrs
+}
+
+// publish publishes event on its channel. Events whose channel cannot be
+// named are rejected with 400.
+func (r *receiver) publish(event cloudevents.Event) protocol.Result {
+	channel, err := r.publisher.channelFor(event)
+	if err != nil {
+		r.logger.Error("Cannot name channel", zap.Error(err))
+		return cehttp.NewResult(http.StatusBadRequest, "cannot name channel: %v", err)
+	}
+	payload, err := r.publisher.payload(event)
+	if err != nil {
+		r.logger.Error("Cannot encode event", zap.Error(err))
+		return cehttp.NewResult(http.StatusBadRequest, "cannot encode event: %v", err)
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	start := time.Now()
+	subscribers, err := redis.Int(conn.Do("PUBLISH", channel, payload))
+	r.recordWrite("PUBLISH", start)
+	if err != nil {
+		r.logger.Error("Cannot publish to channel", zap.String("channel", channel), zap.Error(err))
+		return writeResult(err, "cannot publish to channel")
+	}
+	r.logger.Info("Published event to the channel", zap.String("channel", channel), zap.Int("subscribers", subscribers))
+	return protocol.ResultACK
+}
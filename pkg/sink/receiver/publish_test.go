@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"net/http"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func TestPublisher(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.order")
+	event.SetSource("/orders")
+	event.SetExtension("region", "eu")
+	event.SetData(cloudevents.ApplicationJSON, map[string]int{"total": 3})
+
+	tests := map[string]struct {
+		config      Config
+		wantChannel string
+		wantPayload string
+		wantErr     bool
+	}{
+		"static channel": {
+			config:      Config{PublishChannel: "orders"},
+			wantChannel: "orders",
+			wantPayload: `{"specversion":"1.0","id":"1","source":"/orders","type":"com.example.order","datacontenttype":"application/json","region":"eu","data":{"total":3}}`,
+		},
+		"templated channel with data": {
+			config:      Config{PublishChannel: "{{.type}}.{{.region}}", PublishEncoding: "data"},
+			wantChannel: "com.example.order.eu",
+			wantPayload: `{"total":3}`,
+		},
+		"invalid encoding": {
+			config:  Config{PublishChannel: "orders", PublishEncoding: "fields"},
+			wantErr: true,
+		},
+		"invalid template": {
+			config:  Config{PublishChannel: "{{.type"},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			p, err := parsePublish(&tc.config)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			channel, err := p.channelFor(event)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantChannel, channel)
+
+			payload, err := p.payload(event)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.wantPayload, string(payload))
+		})
+	}
+
+	p, err := parsePublish(&Config{})
+	require.NoError(t, err)
+	require.Nil(t, p, "should add events to the stream without channel")
+}
+
+func TestReceiver_PublishMissingAttribute(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.order")
+	event.SetSource("/orders")
+
+	p, err := parsePublish(&Config{PublishChannel: "orders.{{.subject}}"})
+	require.NoError(t, err)
+
+	r := &receiver{config: &Config{}, logger: zap.NewNop(), publisher: p, metrics: newMetrics(noop.NewMeterProvider())}
+	var result *cehttp.Result
+	require.True(t, protocol.ResultAs(r.Receive(event), &result))
+	require.Equal(t, http.StatusBadRequest, result.StatusCode)
+}
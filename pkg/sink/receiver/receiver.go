@@ -18,108 +18,188 @@ package receiver
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	"knative.dev/eventing/pkg/adapter/v2"
 	"knative.dev/pkg/logging"
 
-	redisParse "github.com/go-redis/redis/v8"
+	"knative.dev/eventing-redis/pkg/encoding"
+	"knative.dev/eventing-redis/pkg/redisconn"
+	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
 )
 
 type Receiver interface {
-	Receive(event cloudevents.Event)
+	Receive(event cloudevents.Event) protocol.Result
 }
 
 type receiver struct {
 	config *Config
 	logger *zap.Logger
 	pool   *redis.Pool
+	trim   *trim
+
+	publisher *publisher
+	pusher    *pusher
+
+	metrics *metrics
 }
 
 func NewEnvConfig() adapter.EnvConfigAccessor {
 	return &Config{}
 }
 
-func NewReceiver(ctx context.Context, processed adapter.EnvConfigAccessor) Receiver {
+func NewReceiver(ctx context.Context, processed adapter.EnvConfigAccessor) (Receiver, error) {
 	config := processed.(*Config)
-	return &receiver{
-		config: config,
-		pool:   newPool(config.Address, config.TLSCertificate),
-		logger: logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
+	trim, err := parseTrim(config)
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := parsePublish(config)
+	if err != nil {
+		return nil, err
+	}
+	pusher, err := parseList(config)
+	if err != nil {
+		return nil, err
 	}
+	pool, err := newPool(config.Config)
+	if err != nil {
+		return nil, err
+	}
+	logger := logging.FromContext(ctx).Desugar()
+	switch {
+	case pusher != nil:
+		logger = logger.With(zap.String("list", config.ListName))
+	case publisher == nil:
+		logger = logger.With(zap.String("stream", config.Stream))
+	}
+	return &receiver{
+		config:    config,
+		pool:      pool,
+		trim:      trim,
+		publisher: publisher,
+		pusher:    pusher,
+		logger:    logger,
+		metrics:   newMetrics(otel.GetMeterProvider()),
+	}, nil
 }
 
-func (r *receiver) Receive(event cloudevents.Event) {
+// Receive adds event to the stream, publishes it on a channel in publish
+// mode, or pushes it on a list in list mode. Malformed events are rejected with 400 and events that cannot be
+// written because Redis is unavailable with 503, so that they are retried.
+func (r *receiver) Receive(event cloudevents.Event) protocol.Result {
+	result := r.receive(event)
+	r.metrics.requests.Add(context.Background(), 1, r.attributes(OutcomeAttr.With(outcome(result))))
+	return result
+}
+
+func (r *receiver) receive(event cloudevents.Event) protocol.Result {
 	r.logger.Info("Receiving event", zap.Any("event", event))
-	conn, _ := r.pool.Dial()
-	defer conn.Close()
 
-	// TODO: validate event
-	var fields []interface{}
-	err := json.Unmarshal(event.Data(), &fields)
+	if r.publisher != nil {
+		return r.publish(event)
+	}
+	if r.pusher != nil {
+		return r.push(event)
+	}
+
+	fields, err := r.encode(event)
 	if err != nil {
-		r.logger.Error("Cannot decode event", zap.Error(err))
-		return
+		r.logger.Error("Cannot encode event", zap.Error(err))
+		return cehttp.NewResult(http.StatusBadRequest, "cannot encode event: %v", err)
 	}
 
-	args := []interface{}{r.config.Stream, "*"}
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	args := []interface{}{r.config.Stream}
+	if r.trim != nil {
+		args = append(args, r.trim.args(time.Now())...)
+	}
+	args = append(args, "*")
 	args = append(args, fields...)
 
+	start := time.Now()
 	_, err = conn.Do("XADD", args...)
+	r.recordWrite("XADD", start)
 	if err != nil {
 		r.logger.Error("Cannot write to stream", zap.Error(err))
+		return writeResult(err, "cannot write to stream")
 	}
 	r.logger.Info("Added event to the stream")
+	return protocol.ResultACK
+}
+
+// encode returns the field/value pairs of the entry storing event.
+func (r *receiver) encode(event cloudevents.Event) ([]interface{}, error) {
+	switch r.config.Encoding {
+	case sinksv1alpha1.EncodingFields:
+		return encoding.EncodeFields(event), nil
+	case sinksv1alpha1.EncodingStructured:
+		return encoding.EncodeStructured(event)
+	default:
+		var fields []interface{}
+		if err := json.Unmarshal(event.Data(), &fields); err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 || len(fields)%2 != 0 {
+			return nil, fmt.Errorf("expected an even number of field/value items (got %d)", len(fields))
+		}
+		for _, field := range fields {
+			switch field.(type) {
+			case string, float64, bool:
+			default:
+				return nil, fmt.Errorf("expected string or number items (got %v)", field)
+			}
+		}
+		return fields, nil
+	}
+}
 
+// writeResult returns the result of a failed write, 503 when Redis is
+// unavailable and 500 otherwise.
+func writeResult(err error, message string) protocol.Result {
+	if isUnavailable(err) {
+		return cehttp.NewResult(http.StatusServiceUnavailable, "redis is unavailable: %v", err)
+	}
+	return cehttp.NewResult(http.StatusInternalServerError, "%s: %v", message, err)
 }
 
-func newPool(address string, tlscert string) *redis.Pool {
-	opt, err := redisParse.ParseURL(address)
-	if err != nil {
-		panic(err)
+// unavailableErrors prefix the Redis errors replied when the server cannot
+// currently accept writes.
+var unavailableErrors = []string{"LOADING", "BUSY", "MASTERDOWN", "CLUSTERDOWN", "TRYAGAIN", "READONLY", "OOM"}
+
+// isUnavailable tells whether err means Redis is unavailable, either because
+// it cannot be reached or because it cannot currently accept writes.
+func isUnavailable(err error) bool {
+	var redisErr redis.Error
+	if !errors.As(err, &redisErr) {
+		return true
 	}
-	return &redis.Pool{
-		// Maximum number of idle connections in the pool.
-		MaxIdle: 80,
-		// max number of connections
-		MaxActive: 12000,
-		// Dial is an application supplied function for creating and
-		// configuring a connection.
-		Dial: func() (redis.Conn, error) {
-			var c redis.Conn
-			if opt.Password != "" && tlscert != "" {
-				roots := x509.NewCertPool()
-				ok := roots.AppendCertsFromPEM([]byte(tlscert))
-				if !ok {
-					panic(err)
-				}
-				c, err = redis.Dial("tcp", opt.Addr,
-					//redis.DialUsername(opt.Username), //username needs to be empty for successful redis connection (v8 go-redis issue)
-					redis.DialPassword(opt.Password),
-					redis.DialTLSConfig(&tls.Config{
-						RootCAs: roots,
-					}),
-					redis.DialTLSSkipVerify(true),
-					redis.DialUseTLS(true),
-					redis.DialDatabase(opt.DB),
-				)
-				if err != nil {
-					panic(err)
-				}
-			} else {
-				c, err = redis.Dial("tcp", opt.Addr,
-					redis.DialDatabase(opt.DB),
-				)
-				if err != nil {
-					panic(err)
-				}
-			}
-			return c, err
-		},
+	for _, prefix := range unavailableErrors {
+		if strings.HasPrefix(string(redisErr), prefix) {
+			return true
+		}
 	}
+	return false
+}
+
+func newPool(config redisconn.Config) (*redis.Pool, error) {
+	// The receiver never sent the username of the address, which needs to be
+	// empty for successful connections (go-redis v8 issue), and only used the
+	// cluster-wide certificate along with a password.
+	config.OmitUsername = true
+	config.LegacyTLSRequiresPassword = true
+	return redisconn.NewPool(config)
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func TestReceiver_Encode(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("dev.knative.sources.redisstream")
+	event.SetSource("cli")
+	event.SetData(cloudevents.ApplicationJSON, []string{"fruit", "orange"})
+
+	tests := map[string][]interface{}{
+		"array": {"fruit", "orange"},
+		"fields": {
+			"ce_specversion", "1.0",
+			"ce_id", "1",
+			"ce_type", "dev.knative.sources.redisstream",
+			"ce_source", "cli",
+			"ce_datacontenttype", "application/json",
+			"data", []byte(`["fruit","orange"]`),
+		},
+	}
+
+	for encoding, want := range tests {
+		t.Run(encoding, func(t *testing.T) {
+			r := &receiver{config: &Config{Encoding: encoding}}
+			got, err := r.encode(event)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+
+	r := &receiver{config: &Config{}}
+	for _, data := range []interface{}{
+		map[string]string{"fruit": "orange"},
+		[]string{"fruit"},
+		[]interface{}{"fruit", []string{"orange"}},
+	} {
+		event.SetData(cloudevents.ApplicationJSON, data)
+		_, err := r.encode(event)
+		require.Error(t, err, "array encoding requires an array of field/value items, got %v", data)
+	}
+}
+
+func TestReceiver_ReceiveMalformed(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("dev.knative.sources.redisstream")
+	event.SetSource("cli")
+	event.SetData(cloudevents.ApplicationJSON, map[string]string{"fruit": "orange"})
+
+	r := &receiver{config: &Config{}, logger: zap.NewNop(), metrics: newMetrics(noop.NewMeterProvider())}
+	var result *cehttp.Result
+	require.True(t, protocol.ResultAs(r.Receive(event), &result))
+	require.Equal(t, http.StatusBadRequest, result.StatusCode)
+}
+
+func TestIsUnavailable(t *testing.T) {
+	require.True(t, isUnavailable(errors.New("dial tcp: connection refused")))
+	require.True(t, isUnavailable(redis.ErrPoolExhausted))
+	require.True(t, isUnavailable(redis.Error("LOADING Redis is loading the dataset in memory")))
+	require.True(t, isUnavailable(redis.Error("READONLY You can't write against a read only replica.")))
+	require.False(t, isUnavailable(redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")))
+}
+
+func TestRetryAfter(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusServiceUnavailable: retryAfterSeconds,
+		http.StatusBadRequest:         "",
+		http.StatusOK:                 "",
+	} {
+		handler := RetryAfter(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+		}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		require.Equal(t, status, rec.Code)
+		require.Equal(t, want, rec.Header().Get("Retry-After"))
+	}
+}
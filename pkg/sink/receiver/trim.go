@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rickb777/date/period"
+)
+
+// trim is the policy applied by every XADD to bound the stream.
+type trim struct {
+	maxLen      int64
+	maxAge      time.Duration
+	approximate bool
+	limit       int64
+}
+
+// parseTrim returns the trim policy set in config, or nil when the stream
+// is not trimmed.
+func parseTrim(config *Config) (*trim, error) {
+	if config.TrimMaxLen == "" && config.TrimMaxAge == "" {
+		return nil, nil
+	}
+	if config.TrimMaxLen != "" && config.TrimMaxAge != "" {
+		return nil, errors.New("only one of trim max length and max age can be set")
+	}
+
+	t := &trim{}
+	if config.TrimMaxLen != "" {
+		maxLen, err := strconv.ParseInt(config.TrimMaxLen, 10, 64)
+		if err != nil || maxLen < 0 {
+			return nil, fmt.Errorf("invalid trim max length %q", config.TrimMaxLen)
+		}
+		t.maxLen = maxLen
+	} else {
+		p, err := period.Parse(config.TrimMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trim max age %q", config.TrimMaxAge)
+		}
+		if t.maxAge, _ = p.Duration(); t.maxAge <= 0 {
+			return nil, fmt.Errorf("invalid trim max age %q", config.TrimMaxAge)
+		}
+	}
+
+	if config.TrimApproximate != "" {
+		approximate, err := strconv.ParseBool(config.TrimApproximate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trim approximate %q", config.TrimApproximate)
+		}
+		t.approximate = approximate
+	}
+
+	if config.TrimLimit != "" {
+		limit, err := strconv.ParseInt(config.TrimLimit, 10, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid trim limit %q", config.TrimLimit)
+		}
+		if !t.approximate {
+			return nil, errors.New("trim limit requires approximate trimming")
+		}
+		t.limit = limit
+	}
+	return t, nil
+}
+
+// args returns the XADD arguments trimming the stream at time now.
+func (t *trim) args(now time.Time) []interface{} {
+	var args []interface{}
+	if t.maxAge > 0 {
+		args = append(args, "MINID")
+	} else {
+		args = append(args, "MAXLEN")
+	}
+	if t.approximate {
+		args = append(args, "~")
+	}
+	if t.maxAge > 0 {
+		// Entry IDs start with their creation time in milliseconds.
+		args = append(args, fmt.Sprintf("%d-0", now.Add(-t.maxAge).UnixNano()/int64(time.Millisecond)))
+	} else {
+		args = append(args, t.maxLen)
+	}
+	if t.limit > 0 {
+		args = append(args, "LIMIT", t.limit)
+	}
+	return args
+}
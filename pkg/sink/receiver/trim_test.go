@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package receiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrim(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+
+	tests := map[string]struct {
+		config  Config
+		want    []interface{}
+		wantErr bool
+	}{
+		"no trimming": {},
+		"max length": {
+			config: Config{TrimMaxLen: "1000"},
+			want:   []interface{}{"MAXLEN", int64(1000)},
+		},
+		"approximate max length with limit": {
+			config: Config{TrimMaxLen: "1000", TrimApproximate: "true", TrimLimit: "100"},
+			want:   []interface{}{"MAXLEN", "~", int64(1000), "LIMIT", int64(100)},
+		},
+		"max age": {
+			config: Config{TrimMaxAge: "PT1H", TrimApproximate: "true"},
+			want:   []interface{}{"MINID", "~", "1599996400000-0"},
+		},
+		"max length and age": {
+			config:  Config{TrimMaxLen: "1000", TrimMaxAge: "PT1H"},
+			wantErr: true,
+		},
+		"invalid max age": {
+			config:  Config{TrimMaxAge: "1h"},
+			wantErr: true,
+		},
+		"limit without approximate": {
+			config:  Config{TrimMaxLen: "1000", TrimLimit: "100"},
+			wantErr: true,
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			trim, err := parseTrim(&tc.config)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.want == nil {
+				require.Nil(t, trim)
+				return
+			}
+			require.Equal(t, tc.want, trim.args(now))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package resources
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,9 +26,14 @@ import (
 
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
 	sinksv1alpha1 "knative.dev/eventing-redis/pkg/sink/apis/sinks/v1alpha1"
 )
 
+// metricsPort is the port the receiver exports its metrics on. The queue
+// proxy of the Knative Service already listens on 9090 and 9091.
+const metricsPort = 9092
+
 func ReceiverName(source *sinksv1alpha1.RedisStreamSink) string {
 	return kmeta.ChildName("redistreamsink", source.Name)
 }
@@ -35,6 +42,33 @@ func ReceiverName(source *sinksv1alpha1.RedisStreamSink) string {
 // RedisStreamSinks
 func MakeReceiver(sink *sinksv1alpha1.RedisStreamSink, image string, tlsCert string) *servingv1.Service {
 	labels := Labels(sink.Name)
+	env := []corev1.EnvVar{{
+		Name:  "STREAM",
+		Value: sink.Spec.Stream,
+	}, {
+		Name:  "ADDRESS",
+		Value: sink.Spec.Address,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name:  "NAMESPACE",
+		Value: sink.Namespace,
+	}, {
+		Name:  "NAME",
+		Value: sink.Name,
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}, eventingresources.ObservabilityEnv(metricsPort)}
+	if sink.Spec.Encoding != "" {
+		env = append(env, corev1.EnvVar{Name: "ENCODING", Value: sink.Spec.Encoding})
+	}
+	env = append(env, trimEnv(sink.Spec.Trim)...)
+	env = append(env, publishEnv(sink.Spec.Publish)...)
+	env = append(env, listEnv(sink.Spec.List)...)
+	env = append(env, eventingresources.ConnectionEnv(sink.Spec.RedisConnection)...)
+
 	return &servingv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: sink.Namespace,
@@ -54,19 +88,7 @@ func MakeReceiver(sink *sinksv1alpha1.RedisStreamSink, image string, tlsCert str
 								{
 									Name:  "receiver",
 									Image: image,
-									Env: []corev1.EnvVar{{
-										Name:  "STREAM",
-										Value: sink.Spec.Stream,
-									}, {
-										Name:  "ADDRESS",
-										Value: sink.Spec.Address,
-									}, {
-										Name:  "TLS_CERTIFICATE",
-										Value: tlsCert,
-									}, {
-										Name:  "METRICS_DOMAIN",
-										Value: "knative.dev/eventing",
-									}},
+									Env:   env,
 								},
 							},
 						},
@@ -76,3 +98,57 @@ func MakeReceiver(sink *sinksv1alpha1.RedisStreamSink, image string, tlsCert str
 		},
 	}
 }
+
+// publishEnv returns the environment variables configuring the channel the
+// receiver publishes events on.
+func publishEnv(publish *sinksv1alpha1.PublishSpec) []corev1.EnvVar {
+	if publish == nil {
+		return nil
+	}
+	env := []corev1.EnvVar{{Name: "PUBLISH_CHANNEL", Value: publish.Channel}}
+	if publish.Encoding != "" {
+		env = append(env, corev1.EnvVar{Name: "PUBLISH_ENCODING", Value: publish.Encoding})
+	}
+	return env
+}
+
+// listEnv returns the environment variables configuring the list the
+// receiver pushes events on.
+func listEnv(list *sinksv1alpha1.ListSpec) []corev1.EnvVar {
+	if list == nil {
+		return nil
+	}
+	env := []corev1.EnvVar{{Name: "LIST_NAME", Value: list.Name}}
+	if list.Push != "" {
+		env = append(env, corev1.EnvVar{Name: "LIST_PUSH", Value: list.Push})
+	}
+	if list.MaxLen != nil {
+		env = append(env, corev1.EnvVar{Name: "LIST_MAX_LEN", Value: strconv.FormatInt(*list.MaxLen, 10)})
+	}
+	if list.Encoding != "" {
+		env = append(env, corev1.EnvVar{Name: "LIST_ENCODING", Value: list.Encoding})
+	}
+	return env
+}
+
+// trimEnv returns the environment variables configuring how the receiver
+// trims the stream.
+func trimEnv(trim *sinksv1alpha1.TrimSpec) []corev1.EnvVar {
+	if trim == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if trim.MaxLen != nil {
+		env = append(env, corev1.EnvVar{Name: "TRIM_MAXLEN", Value: strconv.FormatInt(*trim.MaxLen, 10)})
+	}
+	if trim.MaxAge != nil {
+		env = append(env, corev1.EnvVar{Name: "TRIM_MAX_AGE", Value: *trim.MaxAge})
+	}
+	if trim.Approximate != nil {
+		env = append(env, corev1.EnvVar{Name: "TRIM_APPROXIMATE", Value: strconv.FormatBool(*trim.Approximate)})
+	}
+	if trim.Limit != nil {
+		env = append(env, corev1.EnvVar{Name: "TRIM_LIMIT", Value: strconv.FormatInt(*trim.Limit, 10)})
+	}
+	return env
+}
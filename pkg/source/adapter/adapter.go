@@ -18,32 +18,48 @@ package adapter
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	"knative.dev/eventing-redis/pkg/encoding"
+	"knative.dev/eventing-redis/pkg/redisconn"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 	scan "knative.dev/eventing-redis/pkg/source/redis"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
-	redisParse "github.com/go-redis/redis/v8"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/cloudevents/sdk-go/v2/types"
 	"github.com/gomodule/redigo/redis"
+	"github.com/rickb777/date/period"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 	"knative.dev/eventing/pkg/adapter/v2"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/eventfilter"
 	"knative.dev/pkg/logging"
 )
 
 const (
 	// RedisStreamSourceEventType is the default RedisStreamSource CloudEvent type.
 	RedisStreamSourceEventType = "dev.knative.sources.redisstream"
-	blockms                    = 5000                  // block for 5s before timing out
-	count                      = 1                     // read one redis entry at a time
-	retryNumTimes              = 5                     // maximum number for retries  TODO: Can move this to config?
-	retryWaitPeriod            = 50 * time.Millisecond // amount of time to wait (50ms) TODO: Can move this to config?
+
+	// StreamExtension is the extension holding the name of the stream an
+	// event was read from.
+	StreamExtension = "redisstream"
+
+	// TTLExtension is the extension holding the number of times the events
+	// of a broker can still be replied to it, as in the brokers of eventing.
+	TTLExtension = "knativebrokerttl"
+
+	blockms         = 5000                  // default time to block (5s) before timing out
+	count           = 1                     // default number of redis entries read at a time
+	retryNumTimes   = 5                     // default maximum number for retries
+	retryWaitPeriod = 50 * time.Millisecond // default amount of time to wait (50ms)
 )
 
 func NewEnvConfig() adapter.EnvConfigAccessor {
@@ -55,65 +71,131 @@ type Adapter struct {
 	logger *zap.Logger
 	client cloudevents.Client
 	source string
+
+	// streams are the streams read by the consumers. They change over time
+	// when streams are discovered.
+	streamsMu sync.RWMutex
+	streams   []string
+
+	discoveryInterval time.Duration
+	rediscover        chan struct{}
+
+	retry         int
+	backoffPolicy eventingduckv1.BackoffPolicyType
+	backoffDelay  time.Duration
+
+	batchSize int
+	blockms   int
+	startID   string
+
+	reclaimMinIdleTime time.Duration
+	reclaimInterval    time.Duration
+
+	// filter, when set, selects the events delivered to the sink.
+	filter eventfilter.Filter
+
+	metrics *metrics
 }
 
 func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
 	config := processed.(*Config)
 
+	logger := logging.FromContext(ctx).Desugar()
+	if config.List != "" {
+		return &Adapter{
+			config:  config,
+			logger:  logger.With(zap.String("list", config.List)),
+			client:  ceClient,
+			source:  fmt.Sprintf("%s/%s", config.Address, config.List),
+			metrics: newMetrics(otel.GetMeterProvider()),
+		}
+	}
+
+	streams := config.StreamNames()
+	if config.StreamPattern != "" {
+		logger = logger.With(zap.String("streamPattern", config.StreamPattern))
+	}
 	return &Adapter{
-		config: config,
-		logger: logging.FromContext(ctx).Desugar().With(zap.String("stream", config.Stream)),
-		client: ceClient,
-		source: fmt.Sprintf("%s/%s", config.Address, config.Stream),
+		config:     config,
+		logger:     logger.With(zap.Strings("streams", streams)),
+		client:     ceClient,
+		source:     config.Address,
+		streams:    streams,
+		rediscover: make(chan struct{}, 1),
+		metrics:    newMetrics(otel.GetMeterProvider()),
 	}
 }
 
+// NewFilteredAdapter returns an adapter only delivering the events passing
+// filter to the sink. The other events are acknowledged without being
+// delivered.
+func NewFilteredAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client, filter eventfilter.Filter) adapter.Adapter {
+	a := NewAdapter(ctx, processed, ceClient).(*Adapter)
+	a.filter = filter
+	return a
+}
+
 func (a *Adapter) Start(ctx context.Context) error {
+	if err := a.parseReadOptions(); err != nil {
+		a.logger.Error("Invalid read options", zap.Error(err))
+		return err
+	}
+	if err := a.parseDeliveryOptions(); err != nil {
+		a.logger.Error("Invalid delivery options", zap.Error(err))
+		return err
+	}
+	if err := a.parseReclaimOptions(); err != nil {
+		a.logger.Error("Invalid reclaim options", zap.Error(err))
+		return err
+	}
+	if err := a.parseDiscoveryOptions(); err != nil {
+		a.logger.Error("Invalid discovery options", zap.Error(err))
+		return err
+	}
+
+	if a.config.List != "" {
+		return a.startList(ctx)
+	}
 
 	waitGroup := &sync.WaitGroup{}
-	pool := a.newPool(a.config.Address)
+	pool := a.newPool()
 
 	conn, err := pool.Dial()
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
+
+	if len(a.streams) == 0 && a.config.StreamPattern == "" {
+		return errors.New("no stream to consume")
+	}
+	if a.config.ClusterMode && !redisconn.SameSlot(a.streams...) {
+		// The streams are read with a single XREADGROUP.
+		return errors.New("the streams must share a hash slot in cluster mode")
+	}
 
-	streamName := a.config.Stream
 	groupName := a.config.Group
 	if groupName == "" { //No group was specified in Source Spec
 		groupName = a.config.PodName // Build consumer group name from stateful set pod name of adapter
 	}
 
-	a.logger.Info("Retrieving group info", zap.String("group", groupName))
-	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
-
-	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "no such key") || strings.Contains(strings.ToLower(err.Error()), "no longer exists") {
-			// stream does not exist, may have been deleted accidentally
-			a.logger.Info("Creating stream and consumer group", zap.String("group", groupName))
-			//XGROUP CREATE creates the stream automatically, if it doesn't exist, when MKSTREAM subcommand is specified as last argument
-			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, "$", "MKSTREAM")
-			if err != nil {
-				a.logger.Error("Cannot create stream and consumer group", zap.Error(err))
-				return err
-			}
-		} else {
+	// Consumer groups belong to a stream, so each stream has its own group.
+	for _, streamName := range a.streams {
+		if err := a.createGroup(conn, streamName, groupName, true); err != nil {
 			return err
 		}
+	}
 
-	} else {
-
-		if _, ok := groups[groupName]; ok {
-			a.logger.Info("Reusing consumer group", zap.String("group", groupName))
-		} else {
-			a.logger.Info("Creating consumer group", zap.String("group", groupName))
-			_, err := conn.Do("XGROUP", "CREATE", streamName, groupName, "$")
-			if err != nil {
-				a.logger.Error("Cannot create consumer group", zap.Error(err))
-				return err
-			}
+	if a.config.StreamPattern != "" {
+		if err := a.discoverStreams(conn, groupName); err != nil {
+			a.logger.Error("Cannot discover streams", zap.Error(err))
+			return err
 		}
-
+		waitGroup.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			a.discover(ctx, pool, groupName)
+		}(waitGroup)
 	}
 
 	numConsumers, err := strconv.Atoi(a.config.NumConsumers)
@@ -129,9 +211,12 @@ func (a *Adapter) Start(ctx context.Context) error {
 		go func(wg *sync.WaitGroup, j int) {
 			defer wg.Done()
 
-			conn, _ := pool.Dial()
+			conn, err := a.dial(ctx, pool)
+			if err != nil {
+				return
+			}
 
-			consumerName := fmt.Sprintf("%s-%d", groupName, j)
+			consumerName := a.consumerName(j)
 			xreadID := "0" //Initial ID to read pending messages
 			a.logger.Info("Listening for messages", zap.String("consumerName", consumerName))
 
@@ -139,13 +224,17 @@ func (a *Adapter) Start(ctx context.Context) error {
 				select {
 				case <-ctx.Done(): //received a SIGINT or SIGTERM signal. Need to process pending messages and shut down consumer group
 
-					for xreadID == "0" {
-						xreadID = a.processEntry(ctx, conn, streamName, groupName, consumerName, xreadID, true)
+					var err error
+					streams := a.currentStreams()
+					for len(streams) > 0 && xreadID == "0" && err == nil {
+						xreadID, err = a.processEntries(ctx, conn, streams, groupName, consumerName, xreadID, true)
 					}
 
-					_, err := conn.Do("XGROUP", "DELCONSUMER", streamName, groupName, consumerName)
 					if err != nil {
-						a.logger.Error("Cannot delete consumer", zap.Error(err))
+						// Deleting the consumer would make its pending messages unclaimable.
+						a.logger.Warn("Keeping consumer with undelivered pending messages", zap.String("consumerName", consumerName), zap.Error(err))
+					} else {
+						a.deleteConsumer(conn, streams, groupName, consumerName)
 					}
 
 					a.logger.Info("Consumer shut down", zap.String("consumerName", consumerName))
@@ -153,150 +242,515 @@ func (a *Adapter) Start(ctx context.Context) error {
 					conn.Close()
 					return
 				default:
-					xreadID = a.processEntry(ctx, conn, streamName, groupName, consumerName, xreadID, false)
+					streams := a.currentStreams()
+					if len(streams) == 0 {
+						// Wait for streams to be discovered.
+						select {
+						case <-ctx.Done():
+						case <-time.After(time.Duration(a.blockms) * time.Millisecond):
+						}
+						continue
+					}
+					xreadID, err = a.processEntries(ctx, conn, streams, groupName, consumerName, xreadID, false)
+					if err != nil && a.config.StreamPattern != "" && isNoSuchStream(err) {
+						// A discovered stream disappeared, stop reading it.
+						a.requestDiscovery()
+					}
+					if err != nil && conn.Err() != nil {
+						// The connection is broken, for instance after a failover. The consumer
+						// group keeps the pending entries, so read them again once reconnected.
+						a.logger.Warn("Reconnecting", zap.String("consumerName", consumerName), zap.Error(err))
+						conn.Close()
+						if conn, err = a.dial(ctx, pool); err != nil {
+							return
+						}
+						xreadID = "0"
+					}
 				}
 			}
 		}(waitGroup, i)
 	}
 
+	waitGroup.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		a.reclaim(ctx, pool, groupName, a.consumerName(0))
+	}(waitGroup)
+
+	waitGroup.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		a.observeGroups(ctx, pool, groupName)
+	}(waitGroup)
+
 	waitGroup.Wait() // wait for all consumers
 
 	a.logger.Info("Quit signal received, gracefully shutdown all consumers.")
 
-	_, err = conn.Do("XGROUP", "DESTROY", streamName, groupName)
-	if err != nil {
-		a.logger.Error("Cannot destroy consumer group", zap.Error(err))
-		return err
+	if a.config.GroupRetention == sourcesv1alpha1.GroupRetentionDelete {
+		if conn.Err() != nil {
+			// Reconnect, the master may have changed since the consumer group was set up.
+			conn = pool.Get()
+			defer conn.Close()
+		}
+		for _, streamName := range a.currentStreams() {
+			if _, err := conn.Do("XGROUP", "DESTROY", streamName, groupName); err != nil {
+				a.logger.Error("Cannot destroy consumer group", zap.String("stream", streamName), zap.Error(err))
+				return err
+			}
+		}
 	}
-	conn.Close()
 
 	a.logger.Info("Done. All consumers are stopped now.")
 
 	return nil
 }
 
-func (a *Adapter) processEntry(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, xreadID string, isShuttingDown bool) string {
-	// Retry configuration. Can retry more times to not lose events.
-	ctx = cloudevents.ContextWithRetriesExponentialBackoff(ctx, retryWaitPeriod, retryNumTimes)
+// consumerName returns the name of the i-th consumer of this pod. Consumers
+// are named after the pod, so that each replica only reads, and deletes on
+// shutdown, its own consumers when the consumer group is shared.
+func (a *Adapter) consumerName(i int) string {
+	return fmt.Sprintf("%s-%d", a.config.PodName, i)
+}
 
+// deleteConsumer deletes the consumer from the consumer groups of the streams,
+// unless it still has pending entries. Deleting it would drop them, they are
+// instead claimed by another consumer once idle for long enough.
+func (a *Adapter) deleteConsumer(conn redis.Conn, streamNames []string, groupName string, consumerName string) {
+	for _, streamName := range streamNames {
+		logger := a.logger.With(zap.String("stream", streamName), zap.String("consumerName", consumerName))
+
+		pending, err := scan.ScanXPendingReply(conn.Do("XPENDING", streamName, groupName, "-", "+", 1, consumerName))
+		if err != nil {
+			logger.Error("Cannot get pending messages", zap.Error(err))
+			continue
+		}
+		if len(pending) > 0 {
+			logger.Warn("Keeping consumer with pending messages")
+			continue
+		}
+		if _, err := conn.Do("XGROUP", "DELCONSUMER", streamName, groupName, consumerName); err != nil {
+			logger.Error("Cannot delete consumer", zap.Error(err))
+		}
+	}
+}
+
+// createGroup creates the consumer group of the stream unless it already
+// exists. The stream itself is created when it does not exist and mkStream
+// is set.
+func (a *Adapter) createGroup(conn redis.Conn, streamName string, groupName string, mkStream bool) error {
+	logger := a.logger.With(zap.String("stream", streamName), zap.String("group", groupName))
+
+	logger.Info("Retrieving group info")
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
+	if err != nil {
+		if !mkStream || !isNoSuchStream(err) {
+			return err
+		}
+		// stream does not exist, may have been deleted accidentally
+		logger.Info("Creating stream and consumer group")
+		//XGROUP CREATE creates the stream automatically, if it doesn't exist, when MKSTREAM subcommand is specified as last argument
+		if _, err := conn.Do("XGROUP", "CREATE", streamName, groupName, a.startID, "MKSTREAM"); err != nil {
+			logger.Error("Cannot create stream and consumer group", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	if _, ok := groups[groupName]; ok {
+		logger.Info("Reusing consumer group")
+		return nil
+	}
+	logger.Info("Creating consumer group")
+	if _, err := conn.Do("XGROUP", "CREATE", streamName, groupName, a.startID); err != nil {
+		logger.Error("Cannot create consumer group", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// processEntries reads a batch of entries from the streams, delivers them and
+// acknowledges the delivered ones. It returns the ID to read from in the next
+// iteration. A non-nil error means some entries were not processed.
+func (a *Adapter) processEntries(ctx context.Context, conn redis.Conn, streamNames []string, groupName string, consumerName string, xreadID string, isShuttingDown bool) (string, error) {
 	//XREAD reads all the pending messages when xreadID=="0" and new messages when xreadID==">"
-	reply, err := conn.Do("XREADGROUP", "GROUP", groupName, consumerName, "COUNT", count, "BLOCK", blockms, "STREAMS", streamName, xreadID)
+	args := make([]interface{}, 0, 8+2*len(streamNames))
+	args = append(args, "GROUP", groupName, consumerName, "COUNT", a.batchSize, "BLOCK", a.blockms, "STREAMS")
+	for _, streamName := range streamNames {
+		args = append(args, streamName)
+	}
+	for range streamNames {
+		args = append(args, xreadID)
+	}
+	reply, err := conn.Do("XREADGROUP", args...)
 	if err != nil {
 		a.logger.Error("Cannot read from stream", zap.Error(err))
 		if !isShuttingDown {
 			time.Sleep(1 * time.Second)
 		}
-		return xreadID
+		return xreadID, err
 	}
 
-	event, err := a.toEvent(reply)
+	elems, err := a.toElements(reply)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "number of items not equal to one (got 0)") || // no more pending messages or
-			strings.Contains(strings.ToLower(err.Error()), "expected a reply of type array") { // Xreadgroup timed out blocking after blockms seconds
-			xreadID = ">" //ID to read new messages in next iteration
-		} else {
-			a.logger.Error("Cannot convert reply", zap.Error(err))
-			if !isShuttingDown {
-				time.Sleep(1 * time.Second)
-			}
+		a.logger.Error("Cannot convert reply", zap.Error(err))
+		if !isShuttingDown {
+			time.Sleep(1 * time.Second)
+		}
+		return xreadID, err
+	}
+
+	count := 0
+	for _, elem := range elems {
+		count += len(elem.Items)
+		if len(elem.Items) > 0 {
+			a.metrics.entriesRead.Add(ctx, int64(len(elem.Items)), a.attributes(StreamAttr.With(elem.Name), GroupAttr.With(groupName)))
 		}
-		return xreadID
 	}
+	if count == 0 {
+		// no more pending messages or Xreadgroup timed out blocking after blockms
+		return ">", nil //ID to read new messages in next iteration
+	}
+
+	a.logger.Debug("Consumer read messages", zap.String("consumerName", consumerName), zap.Int("count", count))
 
-	a.logger.Info("Consumer read a message", zap.String("consumerName", consumerName))
+	// The streams are delivered concurrently, and the entries of each stream
+	// in order.
+	errs := make([][]error, len(elems))
+	var wg sync.WaitGroup
+	for i, elem := range elems {
+		errs[i] = make([]error, len(elem.Items))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			attrs := a.attributes(StreamAttr.With(elems[i].Name), GroupAttr.With(groupName))
+			for j, item := range elems[i].Items {
+				if item.FieldValues == nil {
+					// The entry was deleted while pending, there is nothing to deliver.
+					continue
+				}
+				errs[i][j] = a.deliver(ctx, a.newEvent(elems[i].Name, item), attrs)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Messages that were not delivered are left pending so they are delivered again.
+	// Entries are acknowledged by their ID, which differs from the event ID
+	// with the cloudevents encoding.
+	var deliveryErr error
+	acked := 0
+	for i, elem := range elems {
+		ids := make([]string, 0, len(elem.Items))
+		for j, item := range elem.Items {
+			if errs[i][j] == nil {
+				ids = append(ids, item.ID)
+			} else {
+				deliveryErr = errs[i][j]
+			}
+		}
 
-	if result := a.client.Send(ctx, *event); !cloudevents.IsACK(result) { //  Event is lost
-		a.logger.Error("Failed to send cloudevent", zap.Any("result", result))
+		if ackErr := a.ack(ctx, conn, elem.Name, groupName, ids); ackErr != nil {
+			a.logger.Error("Cannot ack messages", zap.String("stream", elem.Name), zap.Error(ackErr))
+			err = ackErr
+			continue
+		}
+		acked += len(ids)
 	}
 
-	_, err = conn.Do("XACK", streamName, groupName, event.ID())
 	if err != nil {
-		a.logger.Error("Cannot ack message", zap.Error(err))
-		xreadID = "0" //ID to read pending message in next iteration
 		if !isShuttingDown {
 			time.Sleep(1 * time.Second)
 		}
-		return xreadID
+		return "0", err //ID to read pending messages in next iteration
+	}
+	if deliveryErr != nil {
+		// The retries are exhausted. Reading the undelivered entries again right
+		// away would block the new entries behind them, and keep resetting their
+		// idle time. They are claimed again once idle for long enough instead.
+		if !isShuttingDown {
+			time.Sleep(1 * time.Second)
+		}
+		return ">", deliveryErr
 	}
-	a.logger.Info("Consumer acknowledged the message", zap.String("consumerName", consumerName))
-	return xreadID
+	a.logger.Debug("Consumer acknowledged messages", zap.String("consumerName", consumerName), zap.Int("count", acked))
+	return xreadID, nil
 }
 
-func (a *Adapter) newPool(address string) *redis.Pool {
-	opt, err := redisParse.ParseURL(address)
+// ack acknowledges the given entries, in a single pipelined round trip.
+func (a *Adapter) ack(ctx context.Context, conn redis.Conn, streamName string, groupName string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(ids)+2)
+	args = append(args, streamName, groupName)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	if err := conn.Send("XACK", args...); err != nil {
+		return err
+	}
+	if err := conn.Flush(); err != nil {
+		return err
+	}
+	if _, err := conn.Receive(); err != nil {
+		return err
+	}
+	a.metrics.entriesAcked.Add(ctx, int64(len(ids)), a.attributes(StreamAttr.With(streamName), GroupAttr.With(groupName)))
+	return nil
+}
+
+// deliver sends the event to the sink, retrying according to the delivery options.
+// When all retries fail, the event is sent to the dead letter sink, if any.
+// The measurements of the delivery are recorded with attrs.
+func (a *Adapter) deliver(ctx context.Context, event cloudevents.Event, attrs metric.MeasurementOption) error {
+	if a.filter != nil && a.filter.Filter(ctx, event) == eventfilter.FailFilter {
+		a.logger.Debug("Event filtered out", zap.String("id", event.ID()))
+		return nil
+	}
+
+	ctx = a.withRetries(ctx)
+
+	start := time.Now()
+	result := a.send(ctx, event)
+	a.metrics.recordSinkDuration(ctx, time.Since(start), attrs)
+	if cloudevents.IsACK(result) {
+		a.metrics.eventsSent.Add(ctx, 1, attrs)
+		return nil
+	}
+	a.metrics.sendFailures.Add(ctx, 1, attrs)
+	a.logger.Error("Failed to send cloudevent", zap.Any("result", result))
+
+	if a.config.DeadLetterSink == "" {
+		return result
+	}
+
+	event.SetExtension("knativeerrordest", a.config.Sink)
+	var httpResult *cehttp.RetriesResult
+	if cloudevents.ResultAs(result, &httpResult) && httpResult.Result != nil {
+		var res *cehttp.Result
+		if cloudevents.ResultAs(httpResult.Result, &res) {
+			event.SetExtension("knativeerrorcode", res.StatusCode)
+		}
+	}
+
+	dlsResult := a.client.Send(cloudevents.ContextWithTarget(ctx, a.config.DeadLetterSink), event)
+	if !cloudevents.IsACK(dlsResult) {
+		a.logger.Error("Failed to send cloudevent to the dead letter sink", zap.Any("result", dlsResult))
+		return dlsResult
+	}
+	a.logger.Info("Sent cloudevent to the dead letter sink", zap.String("id", event.ID()))
+	return nil
+}
+
+// send sends the event to the sink and, when a reply target is set, the
+// response of the sink, if any, to the reply target.
+func (a *Adapter) send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	if a.config.Reply == "" {
+		return a.client.Send(ctx, event)
+	}
+
+	// The TTL of the event is not delivered to the sink, it is carried to
+	// its response.
+	ttl, hasTTL := int32(0), false
+	if a.config.ReplyTTL {
+		if value, err := types.ToInteger(event.Extensions()[TTLExtension]); err == nil {
+			ttl, hasTTL = value, true
+			event = event.Clone()
+			event.SetExtension(TTLExtension, nil)
+		}
+	}
+
+	response, result := a.client.Request(ctx, event)
+	if !cloudevents.IsACK(result) || response == nil {
+		return result
+	}
+	if hasTTL {
+		if ttl <= 1 {
+			a.logger.Warn("Dropping the response, the TTL of the event expired", zap.String("id", event.ID()))
+			return result
+		}
+		response.SetExtension(TTLExtension, ttl-1)
+	}
+	return a.client.Send(cloudevents.ContextWithTarget(ctx, a.config.Reply), *response)
+}
+
+// withRetries returns a context configured with the retry policy of the delivery options.
+func (a *Adapter) withRetries(ctx context.Context) context.Context {
+	if a.backoffPolicy == eventingduckv1.BackoffPolicyLinear {
+		return cloudevents.ContextWithRetriesLinearBackoff(ctx, a.backoffDelay, a.retry)
+	}
+	return cloudevents.ContextWithRetriesExponentialBackoff(ctx, a.backoffDelay, a.retry)
+}
+
+// parseReadOptions sets the batch size and block time from the config, falling back to defaults.
+func (a *Adapter) parseReadOptions() error {
+	a.batchSize = count
+	if a.config.BatchSize != "" {
+		batchSize, err := strconv.Atoi(a.config.BatchSize)
+		if err != nil || batchSize < 1 {
+			return fmt.Errorf("invalid batch size %q", a.config.BatchSize)
+		}
+		a.batchSize = batchSize
+	}
+
+	a.blockms = blockms
+	if a.config.BlockMilliseconds != "" {
+		block, err := strconv.Atoi(a.config.BlockMilliseconds)
+		if err != nil || block < 1 {
+			return fmt.Errorf("invalid block milliseconds %q", a.config.BlockMilliseconds)
+		}
+		a.blockms = block
+	}
+
+	startID, err := scan.StartID(a.config.StartFrom)
 	if err != nil {
-		panic(err)
+		return err
 	}
+	a.startID = startID
 
-	return &redis.Pool{
-		// Maximum number of idle connections in the pool.
-		MaxIdle: 80,
-		// max number of connections
-		MaxActive: 12000,
-		// Dial is an application supplied function for creating and
-		// configuring a connection.
-		Dial: func() (redis.Conn, error) {
-			var c redis.Conn
-			if a.config.TLSCertificate != "" {
-				roots := x509.NewCertPool()
-				ok := roots.AppendCertsFromPEM([]byte(a.config.TLSCertificate))
-				if !ok {
-					panic(err)
-				}
-				c, err = redis.Dial("tcp", opt.Addr,
-					redis.DialUsername(opt.Username),
-					redis.DialPassword(opt.Password),
-					redis.DialTLSConfig(&tls.Config{
-						RootCAs: roots,
-					}),
-					redis.DialTLSSkipVerify(true),
-					redis.DialUseTLS(true),
-					redis.DialDatabase(opt.DB),
-				)
-				if err != nil {
-					panic(err)
-				}
-			} else {
-				c, err = redis.Dial("tcp", opt.Addr,
-					redis.DialDatabase(opt.DB),
-				)
-				if err != nil {
-					panic(err)
-				}
-			}
-			return c, err
-		},
+	switch a.config.Encoding {
+	case "", sourcesv1alpha1.EncodingArray, sourcesv1alpha1.EncodingCloudEvents:
+	default:
+		return fmt.Errorf("invalid encoding %q", a.config.Encoding)
 	}
+	return nil
 }
 
-func (a *Adapter) toEvent(reply interface{}) (*cloudevents.Event, error) {
-	values, err := redis.Values(reply, nil)
+// parseDeliveryOptions sets the retry policy from the config, falling back to defaults.
+func (a *Adapter) parseDeliveryOptions() error {
+	a.retry = retryNumTimes
+	if a.config.Retry != "" {
+		retry, err := strconv.Atoi(a.config.Retry)
+		if err != nil || retry < 0 {
+			return fmt.Errorf("invalid retry %q", a.config.Retry)
+		}
+		a.retry = retry
+	}
+
+	a.backoffPolicy = eventingduckv1.BackoffPolicyExponential
+	switch eventingduckv1.BackoffPolicyType(a.config.BackoffPolicy) {
+	case "", eventingduckv1.BackoffPolicyExponential:
+	case eventingduckv1.BackoffPolicyLinear:
+		a.backoffPolicy = eventingduckv1.BackoffPolicyLinear
+	default:
+		return fmt.Errorf("invalid backoff policy %q", a.config.BackoffPolicy)
+	}
+
+	delay, err := parseDuration(a.config.BackoffDelay, retryWaitPeriod)
 	if err != nil {
-		return nil, errors.New("expected a reply of type array")
+		return fmt.Errorf("invalid backoff delay: %w", err)
 	}
+	a.backoffDelay = delay
+	return nil
+}
 
-	// Assert only one stream
-	if len(values) != 1 {
-		return nil, fmt.Errorf("number of values not equal to one (got %d)", len(values))
+// parseReclaimOptions sets the reclaim policy from the config, falling back to defaults.
+func (a *Adapter) parseReclaimOptions() error {
+	minIdleTime, err := parseDuration(a.config.ReclaimMinIdleTime, reclaimMinIdleTime)
+	if err != nil {
+		return fmt.Errorf("invalid reclaim min idle time: %w", err)
+	}
+	interval, err := parseDuration(a.config.ReclaimInterval, reclaimInterval)
+	if err != nil {
+		return fmt.Errorf("invalid reclaim interval: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid reclaim interval %q", a.config.ReclaimInterval)
 	}
+	a.reclaimMinIdleTime = minIdleTime
+	a.reclaimInterval = interval
+	return nil
+}
 
-	elems, err := scan.ScanXReadReply(values, nil)
+// parseDiscoveryOptions sets the stream discovery interval from the config,
+// falling back to the default.
+func (a *Adapter) parseDiscoveryOptions() error {
+	interval, err := parseDuration(a.config.DiscoveryInterval, discoveryInterval)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("invalid discovery interval: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid discovery interval %q", a.config.DiscoveryInterval)
 	}
+	if a.config.StreamPattern != "" && a.config.ClusterMode {
+		return errors.New("stream discovery is not supported in cluster mode")
+	}
+	a.discoveryInterval = interval
+	return nil
+}
 
-	// Assert only one item
-	if len(elems[0].Items) != 1 {
-		return nil, fmt.Errorf("number of items not equal to one (got %d)", len(elems[0].Items))
+// parseDuration parses an ISO-8601 duration, returning def when value is empty.
+func parseDuration(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
 	}
+	p, err := period.Parse(value)
+	if err != nil {
+		return 0, err
+	}
+	d, _ := p.Duration()
+	return d, nil
+}
+
+func (a *Adapter) newPool() *redis.Pool {
+	pool, err := redisconn.NewPool(a.config.Config)
+	if err != nil {
+		panic(err)
+	}
+	return pool
+}
+
+// dial connects to Redis, retrying until it succeeds or ctx is done.
+func (a *Adapter) dial(ctx context.Context, pool *redis.Pool) (redis.Conn, error) {
+	for {
+		conn, err := pool.Dial()
+		if err == nil {
+			return conn, nil
+		}
+		a.logger.Error("Cannot connect to Redis", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+}
+
+// toElements converts a XREADGROUP reply to the items read from each stream.
+// A nil reply, returned when the read timed out, has no elements.
+func (a *Adapter) toElements(reply interface{}) (scan.StreamElements, error) {
+	if reply == nil {
+		return nil, nil
+	}
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, errors.New("expected a reply of type array")
+	}
+	return scan.ScanXReadReply(values, nil)
+}
 
-	item := elems[0].Items[0]
+// newEvent converts an item of the stream streamName to a CloudEvent.
+func (a *Adapter) newEvent(streamName string, item scan.StreamItem) cloudevents.Event {
+	if a.config.Encoding == sourcesv1alpha1.EncodingCloudEvents {
+		event, err := encoding.Decode(item.FieldValues)
+		if err != nil {
+			a.logger.Warn("Cannot decode CloudEvent, using array encoding", zap.String("id", item.ID), zap.Error(err))
+		} else if event != nil {
+			// The events stored by a sink are left untouched, unless they
+			// must be told apart from those of the other streams.
+			if a.config.StreamPattern != "" || len(a.currentStreams()) > 1 {
+				event.SetExtension(StreamExtension, streamName)
+			}
+			return *event
+		}
+	}
 
 	event := cloudevents.NewEvent()
 	event.SetType(RedisStreamSourceEventType)
-	event.SetSource(a.source)
+	event.SetSource(fmt.Sprintf("%s/%s", a.source, streamName))
 	event.SetData(cloudevents.ApplicationJSON, item.FieldValues)
 	event.SetID(item.ID)
-
-	return &event, nil
+	event.SetExtension(StreamExtension, streamName)
+	return event
 }
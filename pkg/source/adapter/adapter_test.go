@@ -18,9 +18,21 @@ package adapter
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/eventfilter/attributes"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
 )
 
 func TestAdapter_Start(t *testing.T) {
@@ -33,3 +45,489 @@ func TestAdapter_Start(t *testing.T) {
 
 	cancel()
 }
+
+func TestAdapter_ParseDeliveryOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		retry   int
+		policy  eventingduckv1.BackoffPolicyType
+		delay   time.Duration
+		wantErr bool
+	}{{
+		name:   "defaults",
+		retry:  retryNumTimes,
+		policy: eventingduckv1.BackoffPolicyExponential,
+		delay:  retryWaitPeriod,
+	}, {
+		name:   "linear",
+		config: Config{Retry: "3", BackoffPolicy: "linear", BackoffDelay: "PT2S"},
+		retry:  3,
+		policy: eventingduckv1.BackoffPolicyLinear,
+		delay:  2 * time.Second,
+	}, {
+		name:    "invalid retry",
+		config:  Config{Retry: "-1"},
+		wantErr: true,
+	}, {
+		name:    "invalid policy",
+		config:  Config{BackoffPolicy: "random"},
+		wantErr: true,
+	}, {
+		name:    "invalid delay",
+		config:  Config{BackoffDelay: "2s"},
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &Adapter{config: &tc.config}
+			err := a.parseDeliveryOptions()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.retry, a.retry)
+			require.Equal(t, tc.policy, a.backoffPolicy)
+			require.Equal(t, tc.delay, a.backoffDelay)
+		})
+	}
+}
+
+func TestAdapter_Deliver(t *testing.T) {
+	tests := []struct {
+		name           string
+		deadLetterSink string
+		reply          string
+		respond        bool
+		failing        map[string]bool
+		wantErr        bool
+		wantTargets    []string
+	}{{
+		name:        "sink acks",
+		wantTargets: []string{""},
+	}, {
+		name:        "sink fails without dead letter sink",
+		failing:     map[string]bool{"": true},
+		wantErr:     true,
+		wantTargets: []string{""},
+	}, {
+		name:           "sink fails with dead letter sink",
+		deadLetterSink: "http://dls",
+		failing:        map[string]bool{"": true},
+		wantTargets:    []string{"", "http://dls"},
+	}, {
+		name:           "sink and dead letter sink fail",
+		deadLetterSink: "http://dls",
+		failing:        map[string]bool{"": true, "http://dls": true},
+		wantErr:        true,
+		wantTargets:    []string{"", "http://dls"},
+	}, {
+		name:        "response sent to reply",
+		reply:       "http://reply",
+		respond:     true,
+		wantTargets: []string{"", "http://reply"},
+	}, {
+		name:        "no response to reply",
+		reply:       "http://reply",
+		wantTargets: []string{""},
+	}, {
+		name:           "reply fails with dead letter sink",
+		deadLetterSink: "http://dls",
+		reply:          "http://reply",
+		respond:        true,
+		failing:        map[string]bool{"http://reply": true},
+		wantTargets:    []string{"", "http://reply", "http://dls"},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeClient{failing: tc.failing, respond: tc.respond}
+			a := &Adapter{
+				config:  &Config{DeadLetterSink: tc.deadLetterSink, Reply: tc.reply},
+				logger:  zap.NewNop(),
+				client:  client,
+				metrics: newMetrics(noop.NewMeterProvider()),
+			}
+
+			event := cloudevents.NewEvent()
+			event.SetID("1-0")
+			event.SetType(RedisStreamSourceEventType)
+			event.SetSource("test")
+
+			err := a.deliver(context.Background(), event, a.attributes())
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantTargets, client.targets)
+		})
+	}
+}
+
+func TestAdapter_DeliverFiltered(t *testing.T) {
+	client := &fakeClient{}
+	a := &Adapter{
+		config:  &Config{},
+		logger:  zap.NewNop(),
+		client:  client,
+		metrics: newMetrics(noop.NewMeterProvider()),
+		filter:  attributes.NewAttributesFilter(map[string]string{"type": "com.example.order"}),
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID("1-0")
+	event.SetType(RedisStreamSourceEventType)
+	event.SetSource("test")
+	require.NoError(t, a.deliver(context.Background(), event, a.attributes()))
+	require.Empty(t, client.targets, "filtered out events are not sent")
+
+	event.SetType("com.example.order")
+	require.NoError(t, a.deliver(context.Background(), event, a.attributes()))
+	require.Equal(t, []string{""}, client.targets)
+}
+
+func TestAdapter_DeliverReplyTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		ttl         interface{}
+		wantTargets []string
+		wantTTL     interface{}
+	}{{
+		name:        "decremented",
+		ttl:         int32(2),
+		wantTargets: []string{"", "http://reply"},
+		wantTTL:     int32(1),
+	}, {
+		name:        "expired",
+		ttl:         int32(1),
+		wantTargets: []string{""},
+	}, {
+		name:        "without TTL",
+		wantTargets: []string{"", "http://reply"},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeClient{respond: true}
+			a := &Adapter{
+				config:  &Config{Reply: "http://reply", ReplyTTL: true},
+				logger:  zap.NewNop(),
+				client:  client,
+				metrics: newMetrics(noop.NewMeterProvider()),
+			}
+
+			event := cloudevents.NewEvent()
+			event.SetID("1-0")
+			event.SetType(RedisStreamSourceEventType)
+			event.SetSource("test")
+			if tc.ttl != nil {
+				event.SetExtension(TTLExtension, tc.ttl)
+			}
+
+			require.NoError(t, a.deliver(context.Background(), event, a.attributes()))
+			require.Equal(t, tc.wantTargets, client.targets)
+			require.NotContains(t, client.events[0].Extensions(), TTLExtension, "the TTL is not delivered to the sink")
+			if len(client.events) > 1 {
+				require.Equal(t, tc.wantTTL, client.events[1].Extensions()[TTLExtension])
+			}
+			if tc.ttl != nil {
+				require.Equal(t, tc.ttl, event.Extensions()[TTLExtension], "the event is left unchanged")
+			}
+		})
+	}
+}
+
+// fakeClient records the target of each sent event and fails for the configured targets and event IDs.
+// It responds to requests with the event when respond is set.
+type fakeClient struct {
+	failing    map[string]bool
+	failingIDs map[string]bool
+	delays     map[string]time.Duration
+	respond    bool
+	targets    []string
+	events     []cloudevents.Event
+	mu         sync.Mutex
+}
+
+func (c *fakeClient) Send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	target := ""
+	if u := cecontext.TargetFrom(ctx); u != nil {
+		target = u.String()
+	}
+	time.Sleep(c.delays[event.ID()])
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.targets = append(c.targets, target)
+	c.events = append(c.events, event)
+	if c.failing[target] || c.failingIDs[event.ID()] {
+		return errors.New("send failed")
+	}
+	return protocol.ResultACK
+}
+
+func (c *fakeClient) Request(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+	result := c.Send(ctx, event)
+	if !c.respond || !cloudevents.IsACK(result) {
+		return nil, result
+	}
+	response := event.Clone()
+	return &response, result
+}
+
+func (c *fakeClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	return nil
+}
+
+func TestAdapter_ProcessEntries(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XREADGROUP": []interface{}{
+				[]interface{}{
+					[]byte("mystream"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("bar")}},
+						[]interface{}{[]byte("2-0"), []interface{}{[]byte("foo"), []byte("baz")}},
+					}}},
+			"XACK": int64(1),
+		},
+	}
+	client := &fakeClient{failingIDs: map[string]bool{"2-0": true}}
+	a := &Adapter{
+		config:    &Config{},
+		logger:    zap.NewNop(),
+		client:    client,
+		metrics:   newMetrics(noop.NewMeterProvider()),
+		batchSize: 10,
+		blockms:   1,
+	}
+
+	xreadID, err := a.processEntries(context.Background(), conn, []string{"mystream"}, "mygroup", "mygroup-0", ">", true)
+	require.Error(t, err)
+	require.Equal(t, ">", xreadID, "should leave undelivered messages pending for reclaim")
+	require.Equal(t, [][]interface{}{
+		{"XREADGROUP", "GROUP", "mygroup", "mygroup-0", "COUNT", 10, "BLOCK", 1, "STREAMS", "mystream", ">"},
+		{"XACK", "mystream", "mygroup", "1-0"},
+	}, conn.commands)
+
+	conn.replies["XREADGROUP"] = []interface{}{[]interface{}{[]byte("mystream"), []interface{}{}}}
+	xreadID, err = a.processEntries(context.Background(), conn, []string{"mystream"}, "mygroup", "mygroup-0", "0", true)
+	require.NoError(t, err)
+	require.Equal(t, ">", xreadID, "should read new messages when no more pending")
+}
+
+func TestAdapter_ProcessEntriesInOrder(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XREADGROUP": []interface{}{
+				[]interface{}{
+					[]byte("mystream"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("bar")}},
+						[]interface{}{[]byte("2-0"), []interface{}{[]byte("foo"), []byte("baz")}},
+						[]interface{}{[]byte("3-0"), []interface{}{[]byte("foo"), []byte("qux")}},
+					}}},
+			"XACK": int64(3),
+		},
+	}
+	// The first entries take longer to deliver, so that concurrent deliveries
+	// would complete in the reverse order.
+	client := &fakeClient{delays: map[string]time.Duration{
+		"1-0": 20 * time.Millisecond,
+		"2-0": 10 * time.Millisecond,
+	}}
+	a := &Adapter{
+		config:    &Config{},
+		logger:    zap.NewNop(),
+		client:    client,
+		metrics:   newMetrics(noop.NewMeterProvider()),
+		batchSize: 10,
+		blockms:   1,
+	}
+
+	_, err := a.processEntries(context.Background(), conn, []string{"mystream"}, "mygroup", "mygroup-0", ">", true)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(client.events))
+	for _, event := range client.events {
+		ids = append(ids, event.ID())
+	}
+	require.Equal(t, []string{"1-0", "2-0", "3-0"}, ids)
+}
+
+func TestAdapter_DeleteConsumer(t *testing.T) {
+	a := &Adapter{
+		config: &Config{PodName: "mysource-1"},
+		logger: zap.NewNop(),
+	}
+	require.Equal(t, "mysource-1-2", a.consumerName(2))
+
+	conn := &fakeConn{replies: map[string]interface{}{"XPENDING": []interface{}{}}}
+	a.deleteConsumer(conn, []string{"mystream"}, "mygroup", "mysource-1-0")
+	require.Equal(t, [][]interface{}{
+		{"XPENDING", "mystream", "mygroup", "-", "+", 1, "mysource-1-0"},
+		{"XGROUP", "DELCONSUMER", "mystream", "mygroup", "mysource-1-0"},
+	}, conn.commands)
+
+	conn = &fakeConn{replies: map[string]interface{}{
+		"XPENDING": []interface{}{
+			[]interface{}{[]byte("1-0"), []byte("mysource-1-0"), int64(1000), int64(3)},
+		},
+	}}
+	a.deleteConsumer(conn, []string{"mystream"}, "mygroup", "mysource-1-0")
+	require.Equal(t, []string{"XPENDING"}, commandNames(conn), "should keep the consumer with pending entries")
+}
+
+func TestAdapter_ProcessEntriesAcksEntryIDs(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XREADGROUP": []interface{}{
+				[]interface{}{
+					[]byte("mystream"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{
+							[]byte("ce_specversion"), []byte("1.0"),
+							[]byte("ce_id"), []byte("abc"),
+							[]byte("ce_type"), []byte("com.example.order"),
+							[]byte("ce_source"), []byte("/orders"),
+						}},
+					}}},
+			"XACK": int64(1),
+		},
+	}
+	a := &Adapter{
+		config:    &Config{Encoding: "cloudevents"},
+		logger:    zap.NewNop(),
+		client:    &fakeClient{},
+		metrics:   newMetrics(noop.NewMeterProvider()),
+		batchSize: 10,
+		blockms:   1,
+	}
+
+	_, err := a.processEntries(context.Background(), conn, []string{"mystream"}, "mygroup", "mygroup-0", ">", true)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"XACK", "mystream", "mygroup", "1-0"}, conn.commands[1])
+}
+
+func TestAdapter_ProcessEntriesMultipleStreams(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XREADGROUP": []interface{}{
+				[]interface{}{
+					[]byte("tenant-a"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("bar")}},
+						[]interface{}{[]byte("2-0"), nil},
+					}},
+				[]interface{}{
+					[]byte("tenant-b"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("baz")}},
+					}}},
+			"XACK": int64(1),
+		},
+	}
+	client := &fakeClient{}
+	a := &Adapter{
+		config:    &Config{},
+		logger:    zap.NewNop(),
+		client:    client,
+		metrics:   newMetrics(noop.NewMeterProvider()),
+		source:    "redis:6379",
+		streams:   []string{"tenant-a", "tenant-b"},
+		batchSize: 10,
+		blockms:   1,
+	}
+
+	xreadID, err := a.processEntries(context.Background(), conn, a.streams, "mygroup", "mygroup-0", ">", true)
+	require.NoError(t, err)
+	require.Equal(t, ">", xreadID)
+	require.Equal(t, [][]interface{}{
+		{"XREADGROUP", "GROUP", "mygroup", "mygroup-0", "COUNT", 10, "BLOCK", 1, "STREAMS", "tenant-a", "tenant-b", ">", ">"},
+		{"XACK", "tenant-a", "mygroup", "1-0", "2-0"},
+		{"XACK", "tenant-b", "mygroup", "1-0"},
+	}, conn.commands)
+
+	// The entry deleted while pending is acknowledged without being delivered.
+	require.Len(t, client.events, 2)
+	streams := map[string]string{}
+	for _, event := range client.events {
+		streams[event.Extensions()[StreamExtension].(string)] = event.Source()
+	}
+	require.Equal(t, map[string]string{
+		"tenant-a": "redis:6379/tenant-a",
+		"tenant-b": "redis:6379/tenant-b",
+	}, streams)
+}
+
+func TestAdapter_NewEvent(t *testing.T) {
+	a := &Adapter{
+		config:  &Config{Encoding: "cloudevents"},
+		logger:  zap.NewNop(),
+		source:  "redis:6379",
+		streams: []string{"mystream"},
+	}
+
+	event := a.newEvent("mystream", scan.StreamItem{ID: "1-0", FieldValues: []string{
+		"ce_specversion", "1.0",
+		"ce_id", "abc",
+		"ce_type", "com.example.order",
+		"ce_source", "/orders",
+		"ce_customer", "42",
+		"data", `{"total":3}`,
+	}})
+	require.Equal(t, "abc", event.ID())
+	require.Equal(t, "com.example.order", event.Type())
+	require.Equal(t, "/orders", event.Source())
+	require.Equal(t, "42", event.Extensions()["customer"])
+	require.Equal(t, `{"total":3}`, string(event.Data()))
+	require.NotContains(t, event.Extensions(), StreamExtension)
+
+	// Entries without CloudEvent fall back to the array encoding
+	event = a.newEvent("mystream", scan.StreamItem{ID: "2-0", FieldValues: []string{"foo", "bar"}})
+	require.Equal(t, "2-0", event.ID())
+	require.Equal(t, RedisStreamSourceEventType, event.Type())
+	require.Equal(t, "redis:6379/mystream", event.Source())
+	require.Equal(t, "mystream", event.Extensions()[StreamExtension])
+	require.Equal(t, `["foo","bar"]`, string(event.Data()))
+}
+
+func TestConfig_StreamNames(t *testing.T) {
+	config := &Config{Stream: "tenant-a", Streams: []string{"tenant-a", "tenant-b"}}
+	require.Equal(t, []string{"tenant-a", "tenant-b"}, config.StreamNames())
+
+	require.Empty(t, (&Config{List: "jobs"}).StreamNames())
+}
+
+// fakeConn is a redis connection returning canned replies per command.
+type fakeConn struct {
+	replies  map[string]interface{}
+	commands [][]interface{}
+	pending  []string
+}
+
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Err() error   { return nil }
+
+func (c *fakeConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	if err, ok := c.replies[commandName].(error); ok {
+		return nil, err
+	}
+	return c.replies[commandName], nil
+}
+
+func (c *fakeConn) Send(commandName string, args ...interface{}) error {
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	c.pending = append(c.pending, commandName)
+	return nil
+}
+
+func (c *fakeConn) Flush() error { return nil }
+
+func (c *fakeConn) Receive() (interface{}, error) {
+	commandName := c.pending[0]
+	c.pending = c.pending[1:]
+	return c.replies[commandName], nil
+}
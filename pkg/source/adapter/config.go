@@ -17,15 +17,72 @@ package adapter
 
 import (
 	"knative.dev/eventing/pkg/adapter/v2"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
 )
 
 type Config struct {
 	adapter.EnvConfig
+	redisconn.Config
+
+	Stream       string   `envconfig:"STREAM" required:"true"`
+	Streams      []string `envconfig:"STREAMS"`
+	List         string   `envconfig:"LIST"`
+	Group        string   `envconfig:"GROUP" required:"true"`
+	PodName      string   `envconfig:"NAME" required:"true"`
+	SourceName   string   `envconfig:"SOURCE_NAME"`
+	NumConsumers string   `envconfig:"NUM_CONSUMERS" required:"true"`
+
+	// Read options. Defaults are used when not set.
+	BatchSize         string `envconfig:"BATCH_SIZE"`
+	BlockMilliseconds string `envconfig:"BLOCK_MILLISECONDS"`
+	Encoding          string `envconfig:"ENCODING"`
+
+	// GroupRetention is when the consumer group is destroyed. The receive
+	// adapter only destroys it on shutdown when it is Delete, otherwise the
+	// controller does when the source is deleted.
+	GroupRetention string `envconfig:"GROUP_RETENTION"`
+
+	// StartFrom is the position from which a new consumer group reads the stream.
+	StartFrom string `envconfig:"START_FROM"`
+
+	// Delivery options. Defaults are used when not set.
+	Retry          string `envconfig:"RETRY"`
+	BackoffPolicy  string `envconfig:"BACKOFF_POLICY"`
+	BackoffDelay   string `envconfig:"BACKOFF_DELAY"`
+	DeadLetterSink string `envconfig:"DEAD_LETTER_SINK"`
+
+	// Reply is where the responses of the sink are sent. They are dropped
+	// when it is not set.
+	Reply string `envconfig:"REPLY"`
+
+	// ReplyTTL bounds the number of times the responses to the events are
+	// sent to the reply with the TTLExtension of the events, which is
+	// decremented in the responses. A response is dropped once the TTL
+	// expires, so that sinks replying with the events they receive do not
+	// loop forever. Events without TTL are not bounded.
+	ReplyTTL bool `envconfig:"REPLY_TTL"`
+
+	// StreamPattern is the glob-style pattern of the streams discovered
+	// every DiscoveryInterval, an ISO-8601 duration.
+	StreamPattern     string `envconfig:"STREAM_PATTERN"`
+	DiscoveryInterval string `envconfig:"DISCOVERY_INTERVAL"`
+
+	// Reclaim options, as ISO-8601 durations. Defaults are used when not set.
+	ReclaimMinIdleTime string `envconfig:"RECLAIM_MIN_IDLE_TIME"`
+	ReclaimInterval    string `envconfig:"RECLAIM_INTERVAL"`
+}
 
-	Address        string `envconfig:"ADDRESS" required:"true"`
-	Stream         string `envconfig:"STREAM" required:"true"`
-	Group          string `envconfig:"GROUP" required:"true"`
-	PodName        string `envconfig:"NAME" required:"true"`
-	NumConsumers   string `envconfig:"NUM_CONSUMERS" required:"true"`
-	TLSCertificate string `envconfig:"TLS_CERTIFICATE" required:"true"`
+// StreamNames returns the names of the streams to consume, the stream
+// followed by the additional streams, without duplicates.
+func (c *Config) StreamNames() []string {
+	names := make([]string, 0, len(c.Streams)+1)
+	seen := make(map[string]bool, len(c.Streams)+1)
+	for _, name := range append([]string{c.Stream}, c.Streams...) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
 }
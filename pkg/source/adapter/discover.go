@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const discoveryInterval = 30 * time.Second // default time between two discoveries of the streams
+
+// discover periodically discovers the streams matching the stream pattern,
+// and whenever a discovered stream is found to be gone.
+func (a *Adapter) discover(ctx context.Context, pool *redis.Pool, groupName string) {
+	ticker := time.NewTicker(a.discoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-a.rediscover:
+		}
+		conn := pool.Get()
+		if err := a.discoverStreams(conn, groupName); err != nil {
+			a.logger.Error("Cannot discover streams", zap.Error(err))
+		}
+		conn.Close()
+	}
+}
+
+// discoverStreams sets the streams read by the consumers to the configured
+// streams and to those matching the stream pattern. The consumer group is
+// created on the streams found for the first time, and the streams that
+// disappeared are no longer read.
+func (a *Adapter) discoverStreams(conn redis.Conn, groupName string) error {
+	matched, err := scan.MatchStreams(conn, a.config.StreamPattern)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool)
+	for _, stream := range a.currentStreams() {
+		current[stream] = true
+	}
+	streams := a.config.StreamNames()
+	known := make(map[string]bool)
+	for _, stream := range streams {
+		known[stream] = true
+	}
+
+	for _, stream := range matched {
+		if known[stream] {
+			continue
+		}
+		known[stream] = true
+		if !current[stream] {
+			// The stream is not created again if it was deleted since the scan.
+			if err := a.createGroup(conn, stream, groupName, false); err != nil {
+				a.logger.Warn("Cannot read discovered stream", zap.String("stream", stream), zap.Error(err))
+				continue
+			}
+			a.logger.Info("Reading discovered stream", zap.String("stream", stream))
+		}
+		streams = append(streams, stream)
+	}
+
+	for stream := range current {
+		if !known[stream] {
+			a.logger.Info("Stream disappeared, no longer reading it", zap.String("stream", stream))
+		}
+	}
+
+	a.streamsMu.Lock()
+	defer a.streamsMu.Unlock()
+	a.streams = streams
+	return nil
+}
+
+// currentStreams returns the streams read by the consumers.
+func (a *Adapter) currentStreams() []string {
+	a.streamsMu.RLock()
+	defer a.streamsMu.RUnlock()
+	return a.streams
+}
+
+// requestDiscovery triggers a discovery of the streams, unless one is
+// already pending.
+func (a *Adapter) requestDiscovery() {
+	select {
+	case a.rediscover <- struct{}{}:
+	default:
+	}
+}
+
+// isNoSuchStream returns true if err reports that a stream does not exist.
+func isNoSuchStream(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such key") || strings.Contains(msg, "no longer exists")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdapter_DiscoverStreams(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"SCAN": []interface{}{
+				[]byte("0"),
+				[]interface{}{[]byte("orders:{acme}"), []byte("orders:{globex}")},
+			},
+			"XINFO":  []interface{}{},
+			"XGROUP": []byte("OK"),
+		},
+	}
+	a := &Adapter{
+		config:  &Config{Stream: "orders", StreamPattern: "orders:*"},
+		logger:  zap.NewNop(),
+		streams: []string{"orders", "orders:{globex}", "orders:{gone}"},
+		startID: "$",
+	}
+
+	require.NoError(t, a.discoverStreams(conn, "mygroup"))
+	require.Equal(t, []string{"orders", "orders:{acme}", "orders:{globex}"}, a.currentStreams())
+	require.Equal(t, [][]interface{}{
+		{"SCAN", "0", "MATCH", "orders:*", "COUNT", 100, "TYPE", "stream"},
+		{"XINFO", "GROUPS", "orders:{acme}"},
+		{"XGROUP", "CREATE", "orders:{acme}", "mygroup", "$"},
+	}, conn.commands, "only the new stream gets a consumer group")
+}
+
+func TestAdapter_DiscoverStreamsDeletedSinceScan(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"SCAN": []interface{}{
+				[]byte("0"),
+				[]interface{}{[]byte("orders:{acme}")},
+			},
+			"XINFO": errors.New("ERR no such key"),
+		},
+	}
+	a := &Adapter{
+		config: &Config{StreamPattern: "orders:*"},
+		logger: zap.NewNop(),
+	}
+
+	require.NoError(t, a.discoverStreams(conn, "mygroup"))
+	require.Empty(t, a.currentStreams())
+	require.Len(t, conn.commands, 2, "the stream is not created again")
+}
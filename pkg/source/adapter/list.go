@@ -0,0 +1,433 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"knative.dev/eventing-redis/pkg/encoding"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// RedisListEventType is the CloudEvent type of the items read from a list.
+const RedisListEventType = "dev.knative.sources.redislist"
+
+// maxListDeliveries is how many times an item is delivered, each time with
+// the retries of the delivery options, before it is moved to the failed list.
+const maxListDeliveries = 10
+
+// listItemSpace is the namespace of the name-based UUIDs identifying the
+// events of the list items.
+var listItemSpace = uuid.MustParse("9b2c1d6e-4f3a-4d8e-a1b7-5c6e2f0a8d34")
+
+// requeueScript moves the items left in the processing list KEYS[1] of the
+// consumer ARGV[1] back to the consumption end of the list KEYS[3], so that
+// they are consumed next, oldest first, and unregisters the consumer from the
+// set KEYS[4]. It does nothing and returns -1 while the consumer heartbeat
+// KEYS[2] is alive, and the number of requeued items otherwise.
+var requeueScript = redis.NewScript(4, `
+if redis.call('EXISTS', KEYS[2]) == 1 then
+  return -1
+end
+local count = 0
+local item = redis.call('LPOP', KEYS[1])
+while item do
+  redis.call('RPUSH', KEYS[3], item)
+  count = count + 1
+  item = redis.call('LPOP', KEYS[1])
+end
+redis.call('SREM', KEYS[4], ARGV[1])
+return count
+`)
+
+// listConsumer consumes a list with the reliable queue pattern: items are
+// atomically moved to the processing list of the consumer, then removed from
+// it once delivered.
+type listConsumer struct {
+	list       string
+	name       string
+	processing string
+	heartbeat  string
+	failed     string
+
+	// pool refreshes the heartbeat while an item is delivered, when set.
+	pool *redis.Pool
+
+	// pending is true when items may be left in the processing list, for
+	// instance after a failed delivery or a restart.
+	pending   bool
+	useBLMove bool
+	nextBeat  time.Time
+
+	// failures is the number of failed deliveries of the oldest item of the
+	// processing list.
+	failures int
+}
+
+func newListConsumer(list string, name string) *listConsumer {
+	return &listConsumer{
+		list:       list,
+		name:       name,
+		processing: processingList(list, name),
+		heartbeat:  heartbeatKey(list, name),
+		failed:     failedList(list),
+		pending:    true,
+		useBLMove:  true,
+	}
+}
+
+// listKeyPrefix returns the prefix of the keys used to consume list. It holds
+// the hash tag of the list, so that all the keys share its hash slot, as
+// required to move items between them in cluster mode.
+func listKeyPrefix(list string) string {
+	if start := strings.IndexByte(list, '{'); start >= 0 && strings.IndexByte(list[start+1:], '}') > 0 {
+		return list
+	}
+	return "{" + list + "}"
+}
+
+// processingList returns the name of the list holding the items being
+// delivered by a consumer.
+func processingList(list string, consumerName string) string {
+	return listKeyPrefix(list) + ":processing:" + consumerName
+}
+
+// heartbeatKey returns the name of the key expiring when a consumer is gone.
+func heartbeatKey(list string, consumerName string) string {
+	return listKeyPrefix(list) + ":heartbeat:" + consumerName
+}
+
+// failedList returns the name of the list holding the items that could not be
+// delivered.
+func failedList(list string) string {
+	return listKeyPrefix(list) + ":failed"
+}
+
+// consumersKey returns the name of the set of the consumers of list.
+func consumersKey(list string) string {
+	return listKeyPrefix(list) + ":consumers"
+}
+
+// startList consumes the list of the config until ctx is done.
+func (a *Adapter) startList(ctx context.Context) error {
+	numConsumers, err := strconv.Atoi(a.config.NumConsumers)
+	if err != nil {
+		a.logger.Error("Cannot convert numConsumers to int", zap.Error(err))
+		return err
+	}
+	a.logger.Info("Number of consumers from config:", zap.Int("NumConsumers", numConsumers))
+
+	waitGroup := &sync.WaitGroup{}
+	pool := a.newPool()
+	listName := a.config.List
+
+	for i := 0; i < numConsumers; i++ {
+		waitGroup.Add(1)
+		go func(j int) {
+			defer waitGroup.Done()
+			consumer := newListConsumer(listName, a.consumerName(j))
+			consumer.pool = pool
+			a.consumeList(ctx, pool, consumer)
+		}(i)
+	}
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		a.requeue(ctx, pool, listName)
+	}()
+
+	waitGroup.Wait()
+
+	a.logger.Info("Done. All consumers are stopped now.")
+	return nil
+}
+
+// consumeList delivers the items of the list on behalf of consumer, starting
+// with the ones left in its processing list by a previous run.
+func (a *Adapter) consumeList(ctx context.Context, pool *redis.Pool, consumer *listConsumer) {
+	conn, err := a.dial(ctx, pool)
+	if err != nil {
+		return
+	}
+	a.logger.Info("Listening for items", zap.String("consumerName", consumer.name))
+
+	for {
+		select {
+		case <-ctx.Done():
+			if conn.Err() != nil {
+				conn.Close()
+				conn = pool.Get()
+			}
+			a.stopListConsumer(conn, consumer)
+			a.logger.Info("Consumer shut down", zap.String("consumerName", consumer.name))
+			conn.Close()
+			return
+		default:
+			err := a.processItem(ctx, conn, consumer)
+			if err != nil && conn.Err() != nil {
+				// The processing list keeps the item being delivered, so read
+				// it again once reconnected.
+				a.logger.Warn("Reconnecting", zap.String("consumerName", consumer.name), zap.Error(err))
+				conn.Close()
+				if conn, err = a.dial(ctx, pool); err != nil {
+					return
+				}
+				consumer.pending = true
+			}
+		}
+	}
+}
+
+// processItem moves an item to the processing list of consumer, delivers it
+// and removes it from the processing list. Items left in the processing list
+// are delivered first. A non-nil error means the item was not processed.
+func (a *Adapter) processItem(ctx context.Context, conn redis.Conn, consumer *listConsumer) error {
+	if err := a.beat(conn, consumer); err != nil {
+		a.logger.Error("Cannot refresh consumer heartbeat", zap.Error(err))
+		time.Sleep(1 * time.Second)
+		return err
+	}
+
+	var value []byte
+	var err error
+	if consumer.pending {
+		value, err = redis.Bytes(conn.Do("LINDEX", consumer.processing, -1))
+		if err == redis.ErrNil {
+			// no more items left in the processing list
+			consumer.pending = false
+			return nil
+		}
+	} else {
+		value, err = a.moveItem(conn, consumer)
+		if err == redis.ErrNil {
+			// timed out blocking after blockms
+			return nil
+		}
+	}
+	if err != nil {
+		a.logger.Error("Cannot read from list", zap.Error(err))
+		time.Sleep(1 * time.Second)
+		return err
+	}
+
+	attrs := a.attributes(ListAttr.With(consumer.list))
+	if !consumer.pending {
+		a.metrics.entriesRead.Add(ctx, 1, attrs)
+	}
+
+	stopBeating := a.keepBeating(consumer)
+	err = a.deliver(ctx, a.newListEvent(value), attrs)
+	stopBeating()
+	if err != nil {
+		// The item stays in the processing list so it is delivered again,
+		// until it failed too many times.
+		consumer.pending = true
+		consumer.failures++
+		if consumer.failures >= maxListDeliveries {
+			a.moveFailedItem(conn, consumer)
+		}
+		time.Sleep(1 * time.Second)
+		return err
+	}
+	consumer.failures = 0
+
+	if _, err := conn.Do("LREM", consumer.processing, -1, value); err != nil {
+		a.logger.Error("Cannot remove delivered item", zap.Error(err))
+		consumer.pending = true
+		time.Sleep(1 * time.Second)
+		return err
+	}
+	a.metrics.entriesAcked.Add(ctx, 1, attrs)
+	return nil
+}
+
+// moveItem waits for an item pushed on the list and moves it to the
+// processing list of consumer. It returns redis.ErrNil when the wait timed out.
+func (a *Adapter) moveItem(conn redis.Conn, consumer *listConsumer) ([]byte, error) {
+	if consumer.useBLMove {
+		value, err := redis.Bytes(conn.Do("BLMOVE", consumer.list, consumer.processing, "RIGHT", "LEFT", float64(a.blockms)/1000))
+		if err == nil || err == redis.ErrNil || !isUnknownCommand(err) {
+			return value, err
+		}
+		a.logger.Info("BLMOVE is not supported, falling back to BRPOPLPUSH")
+		consumer.useBLMove = false
+	}
+	// Timeouts are integers before Redis 6.
+	return redis.Bytes(conn.Do("BRPOPLPUSH", consumer.list, consumer.processing, (a.blockms+999)/1000))
+}
+
+// moveFailedItem moves the item being delivered by consumer, at the end of
+// its processing list, to the failed list, so that the next items are
+// delivered. It is left in the processing list when it cannot be moved.
+func (a *Adapter) moveFailedItem(conn redis.Conn, consumer *listConsumer) {
+	if _, err := conn.Do("RPOPLPUSH", consumer.processing, consumer.failed); err != nil {
+		a.logger.Error("Cannot move undelivered item to the failed list", zap.String("consumerName", consumer.name), zap.Error(err))
+		return
+	}
+	a.logger.Error("Moved undelivered item to the failed list", zap.String("consumerName", consumer.name),
+		zap.String("failedList", consumer.failed), zap.Int("deliveries", consumer.failures))
+	consumer.failures = 0
+}
+
+// heartbeatTTL returns how long the heartbeat of a consumer outlives its
+// last refresh. It outlives a blocking read, so that it only expires when the
+// consumer is gone.
+func (a *Adapter) heartbeatTTL() time.Duration {
+	return a.reclaimMinIdleTime + 2*time.Duration(a.blockms)*time.Millisecond
+}
+
+// beat registers consumer and refreshes its heartbeat when it is due.
+func (a *Adapter) beat(conn redis.Conn, consumer *listConsumer) error {
+	now := time.Now()
+	if now.Before(consumer.nextBeat) {
+		return nil
+	}
+	ttl := a.heartbeatTTL()
+	if _, err := conn.Do("SADD", consumersKey(consumer.list), consumer.name); err != nil {
+		return err
+	}
+	if _, err := conn.Do("SET", consumer.heartbeat, now.Unix(), "PX", ttl.Milliseconds()); err != nil {
+		return err
+	}
+	consumer.nextBeat = now.Add(ttl / 4)
+	return nil
+}
+
+// keepBeating refreshes the heartbeat of consumer with its own connection
+// until the returned function is called, so that it does not expire while
+// an item is delivered for longer than its TTL, for instance with retries.
+func (a *Adapter) keepBeating(consumer *listConsumer) func() {
+	if consumer.pool == nil {
+		return func() {}
+	}
+	ttl := a.heartbeatTTL()
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(ttl / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				conn := consumer.pool.Get()
+				if _, err := conn.Do("SET", consumer.heartbeat, time.Now().Unix(), "PX", ttl.Milliseconds()); err != nil {
+					a.logger.Error("Cannot refresh consumer heartbeat", zap.Error(err))
+				}
+				conn.Close()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// stopListConsumer requeues the items left in the processing list of
+// consumer, for instance after failed deliveries, and unregisters it.
+func (a *Adapter) stopListConsumer(conn redis.Conn, consumer *listConsumer) {
+	if _, err := conn.Do("DEL", consumer.heartbeat); err != nil {
+		// The items are requeued once the heartbeat expires.
+		a.logger.Error("Cannot delete consumer heartbeat", zap.Error(err))
+		return
+	}
+	count, err := redis.Int(requeueScript.Do(conn, consumer.processing, consumer.heartbeat, consumer.list, consumersKey(consumer.list), consumer.name))
+	if err != nil {
+		a.logger.Error("Cannot requeue items", zap.String("consumerName", consumer.name), zap.Error(err))
+		return
+	}
+	if count > 0 {
+		a.logger.Info("Requeued undelivered items", zap.String("consumerName", consumer.name), zap.Int("count", count))
+	}
+}
+
+// requeue periodically requeues the items left in the processing lists of
+// the consumers that are gone, for instance because they crashed or were
+// scaled down.
+func (a *Adapter) requeue(ctx context.Context, pool *redis.Pool, listName string) {
+	ticker := time.NewTicker(a.reclaimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn := pool.Get()
+			if err := a.requeueStaleItems(conn, listName); err != nil {
+				a.logger.Error("Cannot requeue items", zap.Error(err))
+			}
+			conn.Close()
+		}
+	}
+}
+
+// requeueStaleItems requeues the items of the consumers whose heartbeat expired.
+func (a *Adapter) requeueStaleItems(conn redis.Conn, listName string) error {
+	consumers, err := redis.Strings(conn.Do("SMEMBERS", consumersKey(listName)))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, consumerName := range consumers {
+		count, err := redis.Int(requeueScript.Do(conn, processingList(listName, consumerName), heartbeatKey(listName, consumerName), listName, consumersKey(listName), consumerName))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if count > 0 {
+			a.logger.Info("Requeued items of a stale consumer", zap.String("consumerName", consumerName), zap.Int("count", count))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newListEvent converts a list item to a CloudEvent.
+func (a *Adapter) newListEvent(value []byte) cloudevents.Event {
+	if a.config.Encoding == sourcesv1alpha1.EncodingCloudEvents {
+		event, err := encoding.DecodeStructured(value)
+		if err != nil {
+			a.logger.Warn("Cannot decode CloudEvent, using raw item", zap.Error(err))
+		} else if event != nil {
+			return *event
+		}
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetType(RedisListEventType)
+	event.SetSource(a.source)
+	// The ID is derived from the item, so that it does not change when the
+	// item is delivered again.
+	event.SetID(uuid.NewSHA1(listItemSpace, value).String())
+	event.SetData(encoding.ContentType(value), value)
+	return event
+}
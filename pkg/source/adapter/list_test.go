@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func TestListKeys(t *testing.T) {
+	require.Equal(t, "{jobs}:processing:c-0", processingList("jobs", "c-0"))
+	require.Equal(t, "{jobs}:heartbeat:c-0", heartbeatKey("jobs", "c-0"))
+	require.Equal(t, "{jobs}:consumers", consumersKey("jobs"))
+	require.Equal(t, "{jobs}:failed", failedList("jobs"))
+
+	// Lists with a hash tag are not tagged again
+	require.Equal(t, "{tenant}:jobs:processing:c-0", processingList("{tenant}:jobs", "c-0"))
+}
+
+func TestAdapter_ProcessItem(t *testing.T) {
+	conn := &fakeConn{replies: map[string]interface{}{
+		"LINDEX": []byte("job-1"),
+		"LREM":   int64(1),
+	}}
+	client := &fakeClient{}
+	a := &Adapter{
+		config:  &Config{},
+		logger:  zap.NewNop(),
+		client:  client,
+		metrics: newMetrics(noop.NewMeterProvider()),
+		blockms: 2000,
+	}
+	consumer := newListConsumer("jobs", "c-0")
+
+	// Items left in the processing list are delivered first
+	require.NoError(t, a.processItem(context.Background(), conn, consumer))
+	require.Equal(t, []string{"SADD", "SET", "LINDEX", "LREM"}, commandNames(conn))
+	require.Equal(t, []interface{}{"LREM", "{jobs}:processing:c-0", -1, []byte("job-1")}, conn.commands[3])
+	require.True(t, consumer.pending)
+
+	conn.commands = nil
+	conn.replies["LINDEX"] = nil
+	require.NoError(t, a.processItem(context.Background(), conn, consumer))
+	require.Equal(t, []string{"LINDEX"}, commandNames(conn), "heartbeat should not be due")
+	require.False(t, consumer.pending, "should move new items when no more pending")
+
+	// BLMOVE falls back to BRPOPLPUSH before Redis 6.2
+	conn.commands = nil
+	conn.replies["BLMOVE"] = redis.Error("ERR unknown command 'BLMOVE'")
+	conn.replies["BRPOPLPUSH"] = []byte("job-2")
+	client.failing = map[string]bool{"": true}
+	require.Error(t, a.processItem(context.Background(), conn, consumer))
+	require.Equal(t, []string{"BLMOVE", "BRPOPLPUSH"}, commandNames(conn))
+	require.Equal(t, []interface{}{"BLMOVE", "jobs", "{jobs}:processing:c-0", "RIGHT", "LEFT", 2.0}, conn.commands[0])
+	require.Equal(t, []interface{}{"BRPOPLPUSH", "jobs", "{jobs}:processing:c-0", 2}, conn.commands[1])
+	require.False(t, consumer.useBLMove)
+	require.True(t, consumer.pending, "undelivered item should be delivered again")
+	require.Equal(t, 1, consumer.failures)
+
+	// Items failing too many times are moved to the failed list
+	conn.commands = nil
+	conn.replies["LINDEX"] = []byte("job-2")
+	consumer.failures = maxListDeliveries - 1
+	consumer.nextBeat = time.Now().Add(time.Hour)
+	require.Error(t, a.processItem(context.Background(), conn, consumer))
+	require.Equal(t, []string{"LINDEX", "RPOPLPUSH"}, commandNames(conn))
+	require.Equal(t, []interface{}{"RPOPLPUSH", "{jobs}:processing:c-0", "{jobs}:failed"}, conn.commands[1])
+	require.Equal(t, 0, consumer.failures)
+	require.True(t, consumer.pending)
+}
+
+func TestAdapter_RequeueStaleItems(t *testing.T) {
+	conn := &fakeConn{replies: map[string]interface{}{
+		"SMEMBERS": []interface{}{[]byte("a-0"), []byte("b-0")},
+		"EVALSHA":  int64(-1),
+	}}
+	a := &Adapter{
+		config: &Config{},
+		logger: zap.NewNop(),
+	}
+
+	require.NoError(t, a.requeueStaleItems(conn, "jobs"))
+	require.Equal(t, []string{"SMEMBERS", "EVALSHA", "EVALSHA"}, commandNames(conn))
+	require.Equal(t, []interface{}{4, "{jobs}:processing:a-0", "{jobs}:heartbeat:a-0", "jobs", "{jobs}:consumers", "a-0"}, conn.commands[1][2:])
+	require.Equal(t, []interface{}{4, "{jobs}:processing:b-0", "{jobs}:heartbeat:b-0", "jobs", "{jobs}:consumers", "b-0"}, conn.commands[2][2:])
+}
+
+func TestAdapter_NewListEvent(t *testing.T) {
+	a := &Adapter{
+		config: &Config{Encoding: "cloudevents"},
+		logger: zap.NewNop(),
+		source: "redis:6379/jobs",
+	}
+
+	event := a.newListEvent([]byte(`{"specversion":"1.0","id":"abc","type":"com.example.job","source":"/jobs","data":{"size":3}}`))
+	require.Equal(t, "abc", event.ID())
+	require.Equal(t, "com.example.job", event.Type())
+	require.Equal(t, `{"size":3}`, string(event.Data()))
+
+	// Items without CloudEvent are sent as is
+	event = a.newListEvent([]byte(`{"size":3}`))
+	require.NoError(t, event.Validate())
+	require.Equal(t, RedisListEventType, event.Type())
+	require.Equal(t, "redis:6379/jobs", event.Source())
+	require.Equal(t, cloudevents.ApplicationJSON, event.DataContentType())
+	require.Equal(t, `{"size":3}`, string(event.Data()))
+
+	// Redelivered items keep their ID
+	require.Equal(t, event.ID(), a.newListEvent([]byte(`{"size":3}`)).ID())
+
+	event = a.newListEvent([]byte("resize"))
+	require.Equal(t, cloudevents.TextPlain, event.DataContentType())
+	require.NotEqual(t, a.newListEvent([]byte(`{"size":3}`)).ID(), event.ID())
+}
+
+func commandNames(conn *fakeConn) []string {
+	names := make([]string, 0, len(conn.commands))
+	for _, command := range conn.commands {
+		names = append(names, command[0].(string))
+	}
+	return names
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"knative.dev/pkg/observability/attributekey"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	scopeName = "knative.dev/eventing-redis/pkg/source/adapter"
+
+	groupStatsInterval = 15 * time.Second // time between two reads of the consumer group statistics
+)
+
+var (
+	NamespaceAttr  = attributekey.String("k8s.namespace.name")
+	SourceNameAttr = attributekey.String("kn.source.name")
+	StreamAttr     = attributekey.String("redis.stream")
+	ListAttr       = attributekey.String("redis.list")
+	GroupAttr      = attributekey.String("redis.group")
+)
+
+type metrics struct {
+	entriesRead  metric.Int64Counter
+	eventsSent   metric.Int64Counter
+	sendFailures metric.Int64Counter
+	entriesAcked metric.Int64Counter
+	sinkDuration metric.Float64Histogram
+	groupPending metric.Int64Gauge
+	groupLag     metric.Int64Gauge
+}
+
+func newMetrics(provider metric.MeterProvider) *metrics {
+	var (
+		m   metrics
+		err error
+	)
+
+	meter := provider.Meter(scopeName)
+
+	m.entriesRead, err = meter.Int64Counter(
+		"kn.redis.source.entries.read",
+		metric.WithDescription("Number of entries read from the stream or the list."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.eventsSent, err = meter.Int64Counter(
+		"kn.redis.source.events.sent",
+		metric.WithDescription("Number of events accepted by the sink."),
+		metric.WithUnit("{event}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.sendFailures, err = meter.Int64Counter(
+		"kn.redis.source.events.failed",
+		metric.WithDescription("Number of events the sink failed to accept after all retries."),
+		metric.WithUnit("{event}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.entriesAcked, err = meter.Int64Counter(
+		"kn.redis.source.entries.acked",
+		metric.WithDescription("Number of entries acknowledged, or removed from the processing list."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.sinkDuration, err = meter.Float64Histogram(
+		"kn.redis.source.sink.duration",
+		metric.WithDescription("The duration of the delivery of an event to the sink, retries included."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.groupPending, err = meter.Int64Gauge(
+		"kn.redis.source.group.pending",
+		metric.WithDescription("Number of entries delivered to the consumer group but not acknowledged yet."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.groupLag, err = meter.Int64Gauge(
+		"kn.redis.source.group.lag",
+		metric.WithDescription("Number of entries of the stream not delivered to the consumer group yet. Requires Redis 7.0 or later."),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return &m
+}
+
+// attributes returns the attributes of the measurements of the source, along
+// with the given ones.
+func (a *Adapter) attributes(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributes(append([]attribute.KeyValue{
+		NamespaceAttr.With(a.config.Namespace),
+		SourceNameAttr.With(a.config.SourceName),
+	}, attrs...)...)
+}
+
+func (m *metrics) recordSinkDuration(ctx context.Context, d time.Duration, opts ...metric.RecordOption) {
+	m.sinkDuration.Record(ctx, float64(d)/float64(time.Second), opts...)
+}
+
+// observeGroups periodically records the pending count and the lag of the
+// consumer group on each stream, as reported by XINFO GROUPS.
+func (a *Adapter) observeGroups(ctx context.Context, pool *redis.Pool, groupName string) {
+	ticker := time.NewTicker(groupStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn := pool.Get()
+			for _, streamName := range a.currentStreams() {
+				if err := a.recordGroupStats(ctx, conn, streamName, groupName); err != nil {
+					a.logger.Warn("Cannot read consumer group statistics", zap.String("stream", streamName), zap.Error(err))
+				}
+			}
+			conn.Close()
+		}
+	}
+}
+
+// recordGroupStats records the pending count and the lag of the consumer
+// group on the stream. The lag is not recorded when Redis cannot tell it.
+func (a *Adapter) recordGroupStats(ctx context.Context, conn redis.Conn, streamName string, groupName string) error {
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", streamName))
+	if err != nil {
+		return err
+	}
+	group, ok := groups[groupName]
+	if !ok {
+		return nil
+	}
+	attrs := a.attributes(StreamAttr.With(streamName), GroupAttr.With(groupName))
+	a.metrics.groupPending.Record(ctx, int64(group.Pending), attrs)
+	if group.Lag != nil {
+		a.metrics.groupLag.Record(ctx, int64(*group.Lag), attrs)
+	}
+	return nil
+}
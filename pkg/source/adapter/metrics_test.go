@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+)
+
+func TestAdapter_Metrics(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XREADGROUP": []interface{}{
+				[]interface{}{
+					[]byte("mystream"),
+					[]interface{}{
+						[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("bar")}},
+						[]interface{}{[]byte("2-0"), []interface{}{[]byte("foo"), []byte("baz")}},
+					}}},
+			"XACK": int64(1),
+			"XINFO": []interface{}{
+				[]interface{}{
+					[]byte("name"), []byte("mygroup"),
+					[]byte("consumers"), int64(1),
+					[]byte("pending"), int64(1),
+					[]byte("last-delivered-id"), []byte("2-0"),
+					[]byte("entries-read"), int64(2),
+					[]byte("lag"), int64(3),
+				}},
+		},
+	}
+	reader := sdkmetric.NewManualReader()
+	a := &Adapter{
+		config:    &Config{EnvConfig: adapter.EnvConfig{Namespace: "ns"}, SourceName: "mysource"},
+		logger:    zap.NewNop(),
+		client:    &fakeClient{failingIDs: map[string]bool{"2-0": true}},
+		metrics:   newMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		batchSize: 10,
+		blockms:   1,
+	}
+
+	_, err := a.processEntries(context.Background(), conn, []string{"mystream"}, "mygroup", "mygroup-0", ">", true)
+	require.Error(t, err)
+	require.NoError(t, a.recordGroupStats(context.Background(), conn, "mystream", "mygroup"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	want := attribute.NewSet(
+		NamespaceAttr.With("ns"),
+		SourceNameAttr.With("mysource"),
+		StreamAttr.With("mystream"),
+		GroupAttr.With("mygroup"),
+	)
+	require.Equal(t, map[string]int64{
+		"kn.redis.source.entries.read":  2,
+		"kn.redis.source.events.sent":   1,
+		"kn.redis.source.events.failed": 1,
+		"kn.redis.source.entries.acked": 1,
+		"kn.redis.source.group.pending": 1,
+		"kn.redis.source.group.lag":     3,
+		"kn.redis.source.sink.duration": 2,
+	}, collectInt64(t, rm, want))
+}
+
+// collectInt64 returns the value of the counters and gauges, and the count of
+// the histograms, measured with the given attributes.
+func collectInt64(t *testing.T, rm metricdata.ResourceMetrics, attrs attribute.Set) map[string]int64 {
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&attrs) {
+						values[m.Name] += dp.Value
+					}
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&attrs) {
+						values[m.Name] = dp.Value
+					}
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&attrs) {
+						values[m.Name] += int64(dp.Count)
+					}
+				}
+			default:
+				t.Fatalf("unexpected data for %s: %T", m.Name, m.Data)
+			}
+		}
+	}
+	return values
+}
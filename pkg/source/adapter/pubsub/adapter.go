@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pubsub implements the receive adapter of the RedisPubSubSource,
+// sending the messages published on Redis Pub/Sub channels as CloudEvents.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/adapter/v2"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-redis/pkg/encoding"
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+const (
+	// RedisPubSubSourceEventType is the RedisPubSubSource CloudEvent type.
+	RedisPubSubSourceEventType = "dev.knative.sources.redispubsub"
+
+	// PatternExtension is the extension holding the pattern matching the
+	// channel, for the messages received through a pattern subscription.
+	PatternExtension = "redispattern"
+
+	resubscribeWaitPeriod = time.Second // time to wait before subscribing again
+	queueSize             = 1000        // number of received messages waiting to be sent
+)
+
+type Config struct {
+	adapter.EnvConfig
+	redisconn.Config
+
+	Channels []string `envconfig:"CHANNELS"`
+	Patterns []string `envconfig:"PATTERNS"`
+
+	// Keyspace notification options.
+	Keyspace              bool     `envconfig:"KEYSPACE"`
+	KeyspaceNotifications string   `envconfig:"KEYSPACE_NOTIFICATIONS"`
+	KeyspaceKeyPrefix     string   `envconfig:"KEYSPACE_KEY_PREFIX"`
+	KeyspaceOperations    []string `envconfig:"KEYSPACE_OPERATIONS"`
+	KeyspaceConfigure     bool     `envconfig:"KEYSPACE_CONFIGURE"`
+	KeyspaceFetchValue    bool     `envconfig:"KEYSPACE_FETCH_VALUE"`
+}
+
+func NewEnvConfig() adapter.EnvConfigAccessor {
+	return &Config{}
+}
+
+type Adapter struct {
+	config *Config
+	logger *zap.Logger
+	client cloudevents.Client
+	source string
+	pool   *redis.Pool
+
+	// queue holds the received messages until they are sent, so that slow
+	// sinks do not hold back the subscription.
+	queue chan redis.Message
+
+	keyspace *keyspace
+}
+
+func NewAdapter(ctx context.Context, processed adapter.EnvConfigAccessor, ceClient cloudevents.Client) adapter.Adapter {
+	config := processed.(*Config)
+
+	source := config.Address
+	if source == "" {
+		// The master or the cluster nodes are discovered.
+		source = "redis://"
+	}
+	return &Adapter{
+		config: config,
+		logger: logging.FromContext(ctx).Desugar(),
+		client: ceClient,
+		source: source,
+		queue:  make(chan redis.Message, queueSize),
+	}
+}
+
+func (a *Adapter) Start(ctx context.Context) error {
+	if len(a.config.Channels) == 0 && len(a.config.Patterns) == 0 && !a.config.Keyspace {
+		return errors.New("no channel, pattern or keyspace to subscribe to")
+	}
+
+	pool, err := redisconn.NewPool(a.config.Config)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	a.pool = pool
+
+	if a.config.Keyspace {
+		if a.keyspace, err = newKeyspace(a.config); err != nil {
+			return err
+		}
+		conn := pool.Get()
+		err := a.keyspace.checkNotifications(conn, a.logger)
+		conn.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.sendQueued(ctx)
+	}()
+	defer wg.Wait()
+
+	for {
+		// Subscriptions are not pooled, since subscribed connections
+		// cannot be reused for other commands.
+		conn, err := pool.Dial()
+		if err == nil {
+			err = a.receive(ctx, conn)
+		}
+		if ctx.Err() != nil {
+			a.logger.Info("Quit signal received, unsubscribed")
+			return nil
+		}
+
+		// Messages published until subscribed again are lost.
+		a.logger.Warn("Subscribing again", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(resubscribeWaitPeriod):
+		}
+	}
+}
+
+// receive subscribes to the channels and patterns and queues the published
+// messages until ctx is done or the connection fails. It closes conn.
+func (a *Adapter) receive(ctx context.Context, conn redis.Conn) error {
+	psc := redis.PubSubConn{Conn: conn}
+
+	// Closing the connection unblocks Receive on shutdown.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		psc.Close()
+	}()
+
+	if len(a.config.Channels) > 0 {
+		if err := psc.Subscribe(redis.Args{}.AddFlat(a.config.Channels)...); err != nil {
+			return err
+		}
+	}
+	patterns := a.config.Patterns
+	if a.keyspace != nil {
+		patterns = append(patterns[:len(patterns):len(patterns)], a.keyspace.pattern)
+	}
+	if len(patterns) > 0 {
+		if err := psc.PSubscribe(redis.Args{}.AddFlat(patterns)...); err != nil {
+			return err
+		}
+	}
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			a.enqueue(v)
+		case redis.Subscription:
+			a.logger.Info("Subscription", zap.String("kind", v.Kind), zap.String("channel", v.Channel), zap.Int("count", v.Count))
+		case error:
+			return v
+		}
+	}
+}
+
+// enqueue queues msg to be sent, or drops it when the queue is full. Waiting
+// would leave the messages in the output buffer of the subscription in Redis,
+// which closes it once the buffer limit is reached.
+func (a *Adapter) enqueue(msg redis.Message) {
+	select {
+	case a.queue <- msg:
+	default:
+		a.logger.Error("Dropping message, too many messages are waiting to be sent", zap.String("channel", msg.Channel))
+	}
+}
+
+// sendQueued sends the events of the queued messages until ctx is done.
+func (a *Adapter) sendQueued(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-a.queue:
+			a.sendMessage(ctx, msg)
+		}
+	}
+}
+
+// sendMessage sends the event of a published message or keyspace
+// notification, if any.
+func (a *Adapter) sendMessage(ctx context.Context, msg redis.Message) {
+	if a.keyspace != nil && msg.Pattern == a.keyspace.pattern {
+		if event := a.newKeyspaceEvent(msg); event != nil {
+			a.send(ctx, *event, msg.Channel)
+		}
+		return
+	}
+	a.send(ctx, a.newEvent(msg), msg.Channel)
+}
+
+// send sends the event of a message published on channel.
+func (a *Adapter) send(ctx context.Context, event cloudevents.Event, channel string) {
+	if result := a.client.Send(ctx, event); !cloudevents.IsACK(result) {
+		// Published messages are not persisted, there is no way to
+		// send them again later.
+		a.logger.Error("Failed to send event", zap.String("channel", channel), zap.String("id", event.ID()), zap.Error(result))
+	}
+}
+
+// newEvent returns the event of a published message. Its subject is the
+// channel the message was published on.
+func (a *Adapter) newEvent(msg redis.Message) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(RedisPubSubSourceEventType)
+	event.SetSource(a.source)
+	event.SetSubject(msg.Channel)
+	if msg.Pattern != "" {
+		event.SetExtension(PatternExtension, msg.Pattern)
+	}
+	event.SetData(encoding.ContentType(msg.Data), msg.Data)
+	return event
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdapter_NewEvent(t *testing.T) {
+	a := &Adapter{source: "redis:6379"}
+
+	event := a.newEvent(redis.Message{Channel: "orders", Data: []byte(`{"total":3}`)})
+	require.NoError(t, event.Validate())
+	require.Equal(t, RedisPubSubSourceEventType, event.Type())
+	require.Equal(t, "redis:6379", event.Source())
+	require.Equal(t, "orders", event.Subject())
+	require.Equal(t, cloudevents.ApplicationJSON, event.DataContentType())
+	require.Equal(t, `{"total":3}`, string(event.Data()))
+	require.NotContains(t, event.Extensions(), PatternExtension)
+
+	event = a.newEvent(redis.Message{Channel: "orders.eu", Pattern: "orders.*", Data: []byte("shipped")})
+	require.Equal(t, "orders.eu", event.Subject())
+	require.Equal(t, "orders.*", event.Extensions()[PatternExtension])
+	require.Equal(t, cloudevents.TextPlain, event.DataContentType())
+
+	event = a.newEvent(redis.Message{Channel: "orders", Data: []byte{0xff, 0xfe}})
+	require.Equal(t, "application/octet-stream", event.DataContentType())
+}
+
+func TestAdapter_Receive(t *testing.T) {
+	client := &fakeClient{}
+	a := &Adapter{
+		config: &Config{Channels: []string{"orders"}, Patterns: []string{"payments.*"}},
+		logger: zap.NewNop(),
+		client: client,
+		source: "redis:6379",
+		queue:  make(chan redis.Message, 10),
+	}
+	conn := &fakeConn{replies: []interface{}{
+		[]interface{}{[]byte("subscribe"), []byte("orders"), int64(1)},
+		[]interface{}{[]byte("psubscribe"), []byte("payments.*"), int64(2)},
+		[]interface{}{[]byte("message"), []byte("orders"), []byte("created")},
+		[]interface{}{[]byte("pmessage"), []byte("payments.*"), []byte("payments.eu"), []byte("paid")},
+	}}
+
+	err := a.receive(context.Background(), conn)
+	require.Error(t, err)
+	require.Equal(t, []string{"SUBSCRIBE", "PSUBSCRIBE"}, conn.commands)
+	require.Empty(t, client.events, "the messages are sent once dequeued")
+
+	for len(a.queue) > 0 {
+		a.sendMessage(context.Background(), <-a.queue)
+	}
+	require.Len(t, client.events, 2)
+	require.Equal(t, "orders", client.events[0].Subject())
+	require.Equal(t, "created", string(client.events[0].Data()))
+	require.Equal(t, "payments.eu", client.events[1].Subject())
+	require.Equal(t, "payments.*", client.events[1].Extensions()[PatternExtension])
+}
+
+func TestAdapter_Enqueue(t *testing.T) {
+	a := &Adapter{logger: zap.NewNop(), queue: make(chan redis.Message, 1)}
+
+	a.enqueue(redis.Message{Channel: "orders", Data: []byte("created")})
+	a.enqueue(redis.Message{Channel: "orders", Data: []byte("paid")})
+	require.Len(t, a.queue, 1, "messages are dropped once the queue is full")
+	require.Equal(t, "created", string((<-a.queue).Data))
+}
+
+// fakeConn is a subscribed redis connection returning canned replies, then
+// failing once they are all received.
+type fakeConn struct {
+	redis.Conn
+	replies  []interface{}
+	commands []string
+}
+
+func (c *fakeConn) Send(commandName string, args ...interface{}) error {
+	c.commands = append(c.commands, commandName)
+	return nil
+}
+
+func (c *fakeConn) Flush() error { return nil }
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Receive() (interface{}, error) {
+	if len(c.replies) == 0 {
+		return nil, errors.New("connection closed")
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
+// fakeClient records the sent events.
+type fakeClient struct {
+	events []cloudevents.Event
+}
+
+func (c *fakeClient) Send(ctx context.Context, event cloudevents.Event) protocol.Result {
+	c.events = append(c.events, event)
+	return protocol.ResultACK
+}
+
+func (c *fakeClient) Request(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	return errors.New("not implemented")
+}
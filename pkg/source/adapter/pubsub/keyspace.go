@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"knative.dev/eventing-redis/pkg/encoding"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+const (
+	// RedisKeyspaceEventType is the CloudEvent type of keyspace notifications.
+	RedisKeyspaceEventType = "dev.knative.sources.redispubsub.keyspace"
+
+	// OperationExtension is the extension holding the operation that
+	// changed the key, for instance set, del or expired.
+	OperationExtension = "redisoperation"
+
+	// keyspaceEventClasses are the notify-keyspace-events flags of the
+	// classes of operations.
+	keyspaceEventClasses = "Ag$lshzxetdmn"
+)
+
+// keyspace subscribes to the keyspace or keyevent notifications of a database.
+type keyspace struct {
+	// prefix is the prefix of the notification channels, followed by the
+	// key, or by the operation for keyevent notifications.
+	prefix string
+	// pattern matches the notification channels of the keys to send.
+	pattern string
+	// keyevent is set for keyevent notifications, which publish the key. The
+	// keys are then matched against keyPrefix once received.
+	keyevent   bool
+	keyPrefix  string
+	operations map[string]bool
+	configure  bool
+	fetchValue bool
+}
+
+func newKeyspace(config *Config) (*keyspace, error) {
+	if config.ClusterMode {
+		// Each node only notifies the changes of its own keys.
+		return nil, errors.New("keyspace notifications are not supported in cluster mode")
+	}
+	db, err := config.Database()
+	if err != nil {
+		return nil, err
+	}
+
+	k := &keyspace{
+		keyPrefix:  config.KeyspaceKeyPrefix,
+		configure:  config.KeyspaceConfigure,
+		fetchValue: config.KeyspaceFetchValue,
+	}
+	switch config.KeyspaceNotifications {
+	case "", sourcesv1alpha1.KeyspaceNotifications:
+		k.prefix = fmt.Sprintf("__keyspace@%d__:", db)
+		k.pattern = k.prefix + escapeGlob(config.KeyspaceKeyPrefix) + "*"
+	case sourcesv1alpha1.KeyeventNotifications:
+		k.prefix = fmt.Sprintf("__keyevent@%d__:", db)
+		k.pattern = k.prefix + "*"
+		k.keyevent = true
+	default:
+		return nil, fmt.Errorf("unknown keyspace notifications %q", config.KeyspaceNotifications)
+	}
+	if len(config.KeyspaceOperations) > 0 {
+		k.operations = make(map[string]bool)
+		for _, op := range config.KeyspaceOperations {
+			k.operations[op] = true
+		}
+	}
+	return k, nil
+}
+
+// checkNotifications checks that the keyspace notifications are enabled, or
+// enables them for all the operations when configured to.
+func (k *keyspace) checkNotifications(conn redis.Conn, logger *zap.Logger) error {
+	reply, err := redis.Strings(conn.Do("CONFIG", "GET", "notify-keyspace-events"))
+	if err != nil || len(reply) != 2 {
+		// Managed Redis services often disable CONFIG and have notifications
+		// configured by other means.
+		logger.Warn("Cannot check keyspace notifications", zap.Error(err))
+		return nil
+	}
+	flags := reply[1]
+	channels := "K"
+	if k.keyevent {
+		channels = "E"
+	}
+
+	if k.configure {
+		missing := ""
+		for _, flag := range channels + "A" {
+			if !strings.ContainsRune(flags, flag) {
+				missing += string(flag)
+			}
+		}
+		if missing == "" {
+			return nil
+		}
+		logger.Info("Enabling keyspace notifications", zap.String("flags", flags+missing))
+		_, err := conn.Do("CONFIG", "SET", "notify-keyspace-events", flags+missing)
+		return err
+	}
+
+	if !strings.Contains(flags, channels) || !strings.ContainsAny(flags, keyspaceEventClasses) {
+		return fmt.Errorf("keyspace notifications are disabled, notify-keyspace-events is %q and must include %s and the event classes, for instance %sA", flags, channels, channels)
+	}
+	return nil
+}
+
+// newKeyspaceEvent returns the event of a keyspace or keyevent notification,
+// or nil when its key or operation is not sent. Its subject is the changed key.
+func (a *Adapter) newKeyspaceEvent(msg redis.Message) *cloudevents.Event {
+	key, operation := strings.TrimPrefix(msg.Channel, a.keyspace.prefix), string(msg.Data)
+	if a.keyspace.keyevent {
+		key, operation = operation, key
+		if !strings.HasPrefix(key, a.keyspace.keyPrefix) {
+			return nil
+		}
+	}
+	if a.keyspace.operations != nil && !a.keyspace.operations[operation] {
+		return nil
+	}
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.New().String())
+	event.SetType(RedisKeyspaceEventType)
+	event.SetSource(a.source)
+	event.SetSubject(key)
+	event.SetExtension(OperationExtension, operation)
+
+	if a.keyspace.fetchValue {
+		conn := a.pool.Get()
+		defer conn.Close()
+		if value, err := fetchValue(conn, key); err != nil {
+			a.logger.Warn("Cannot fetch value", zap.String("key", key), zap.Error(err))
+		} else if value != nil {
+			event.SetData(encoding.ContentType(value), value)
+		}
+	}
+	return &event
+}
+
+// fetchValue returns the current value of key, as JSON for collections, or
+// nil when the key does not exist or is a stream.
+func fetchValue(conn redis.Conn, key string) ([]byte, error) {
+	kind, err := redis.String(conn.Do("TYPE", key))
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch kind {
+	case "string":
+		value, err := redis.Bytes(conn.Do("GET", key))
+		if err == redis.ErrNil {
+			// Deleted since
+			return nil, nil
+		}
+		return value, err
+	case "hash":
+		value, err = redis.StringMap(conn.Do("HGETALL", key))
+	case "list":
+		value, err = redis.Strings(conn.Do("LRANGE", key, 0, -1))
+	case "set":
+		value, err = redis.Strings(conn.Do("SMEMBERS", key))
+	case "zset":
+		value, err = redis.Strings(conn.Do("ZRANGE", key, 0, -1))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// escapeGlob escapes the characters of s having a special meaning in glob-style patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+func TestNewKeyspace(t *testing.T) {
+	k, err := newKeyspace(&Config{
+		Config:             redisconn.Config{Address: "redis://redis:6379/2"},
+		KeyspaceKeyPrefix:  "session:[eu]*",
+		KeyspaceOperations: []string{"expired"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "__keyspace@2__:", k.prefix)
+	require.Equal(t, `__keyspace@2__:session:\[eu\]\**`, k.pattern)
+	require.Equal(t, map[string]bool{"expired": true}, k.operations)
+
+	k, err = newKeyspace(&Config{
+		Config:                redisconn.Config{Address: "redis://redis:6379"},
+		KeyspaceNotifications: "Keyevent",
+		KeyspaceKeyPrefix:     "session:",
+	})
+	require.NoError(t, err)
+	require.True(t, k.keyevent)
+	require.Equal(t, "__keyevent@0__:", k.prefix)
+	require.Equal(t, "__keyevent@0__:*", k.pattern)
+	require.Equal(t, "session:", k.keyPrefix)
+
+	_, err = newKeyspace(&Config{Config: redisconn.Config{Address: "redis://redis:6379"}, KeyspaceNotifications: "Keys"})
+	require.Error(t, err)
+
+	_, err = newKeyspace(&Config{Config: redisconn.Config{Address: "redis://redis:6379", ClusterMode: true}})
+	require.Error(t, err)
+}
+
+func TestKeyspace_CheckNotifications(t *testing.T) {
+	tests := map[string]struct {
+		flags     string
+		keyevent  bool
+		configure bool
+		wantSet   string
+		wantErr   bool
+	}{
+		"enabled":                    {flags: "Kx"},
+		"disabled":                   {flags: "", wantErr: true},
+		"keyevent only":              {flags: "EA", wantErr: true},
+		"no event class":             {flags: "K", wantErr: true},
+		"configure":                  {flags: "", configure: true, wantSet: "KA"},
+		"configure keyevent":         {flags: "Ex", configure: true, wantSet: "ExKA"},
+		"already configured":         {flags: "AK", configure: true},
+		"keyevent enabled":           {flags: "Eg", keyevent: true},
+		"keyspace only for keyevent": {flags: "KA", keyevent: true, wantErr: true},
+		"configure for keyevent":     {flags: "K", keyevent: true, configure: true, wantSet: "KEA"},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			conn := &fakeValueConn{replies: map[string]interface{}{
+				"CONFIG": []interface{}{[]byte("notify-keyspace-events"), []byte(tc.flags)},
+			}}
+			k := &keyspace{keyevent: tc.keyevent, configure: tc.configure}
+			err := k.checkNotifications(conn, zap.NewNop())
+			require.Equal(t, tc.wantErr, err != nil, "unexpected error: %v", err)
+
+			var set []interface{}
+			for _, cmd := range conn.commands {
+				if len(cmd) == 4 && cmd[1] == "SET" {
+					set = cmd
+				}
+			}
+			if tc.wantSet == "" {
+				require.Nil(t, set)
+			} else {
+				require.Equal(t, []interface{}{"CONFIG", "SET", "notify-keyspace-events", tc.wantSet}, set)
+			}
+		})
+	}
+}
+
+func TestAdapter_NewKeyspaceEvent(t *testing.T) {
+	a := &Adapter{
+		logger: zap.NewNop(),
+		source: "redis:6379",
+		keyspace: &keyspace{
+			prefix:     "__keyspace@0__:",
+			pattern:    "__keyspace@0__:session:*",
+			operations: map[string]bool{"set": true, "expired": true},
+		},
+	}
+
+	event := a.newKeyspaceEvent(redis.Message{Channel: "__keyspace@0__:session:42", Pattern: a.keyspace.pattern, Data: []byte("expired")})
+	require.NotNil(t, event)
+	require.NoError(t, event.Validate())
+	require.Equal(t, RedisKeyspaceEventType, event.Type())
+	require.Equal(t, "session:42", event.Subject())
+	require.Equal(t, "expired", event.Extensions()[OperationExtension])
+	require.Nil(t, event.Data())
+
+	require.Nil(t, a.newKeyspaceEvent(redis.Message{Channel: "__keyspace@0__:session:42", Pattern: a.keyspace.pattern, Data: []byte("del")}))
+
+	// Keyevent notifications publish the key on the channel of the operation.
+	a.keyspace = &keyspace{
+		prefix:     "__keyevent@0__:",
+		pattern:    "__keyevent@0__:*",
+		keyevent:   true,
+		keyPrefix:  "session:",
+		operations: map[string]bool{"set": true, "expired": true},
+	}
+	event = a.newKeyspaceEvent(redis.Message{Channel: "__keyevent@0__:expired", Pattern: a.keyspace.pattern, Data: []byte("session:42")})
+	require.NotNil(t, event)
+	require.Equal(t, "session:42", event.Subject())
+	require.Equal(t, "expired", event.Extensions()[OperationExtension])
+
+	require.Nil(t, a.newKeyspaceEvent(redis.Message{Channel: "__keyevent@0__:del", Pattern: a.keyspace.pattern, Data: []byte("session:42")}))
+	require.Nil(t, a.newKeyspaceEvent(redis.Message{Channel: "__keyevent@0__:set", Pattern: a.keyspace.pattern, Data: []byte("cart:42")}))
+}
+
+func TestFetchValue(t *testing.T) {
+	tests := map[string]struct {
+		replies map[string]interface{}
+		want    string
+	}{
+		"string": {
+			replies: map[string]interface{}{"TYPE": "string", "GET": []byte("hello")},
+			want:    "hello",
+		},
+		"hash": {
+			replies: map[string]interface{}{"TYPE": "hash", "HGETALL": []interface{}{[]byte("f"), []byte("v")}},
+			want:    `{"f":"v"}`,
+		},
+		"list": {
+			replies: map[string]interface{}{"TYPE": "list", "LRANGE": []interface{}{[]byte("a"), []byte("b")}},
+			want:    `["a","b"]`,
+		},
+		"deleted": {
+			replies: map[string]interface{}{"TYPE": "none"},
+		},
+		"deleted since": {
+			replies: map[string]interface{}{"TYPE": "string", "GET": nil},
+		},
+		"stream": {
+			replies: map[string]interface{}{"TYPE": "stream"},
+		},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			value, err := fetchValue(&fakeValueConn{replies: tc.replies}, "mykey")
+			require.NoError(t, err)
+			require.Equal(t, tc.want, string(value))
+		})
+	}
+}
+
+// fakeValueConn is a redis connection returning canned replies per command.
+type fakeValueConn struct {
+	redis.Conn
+	replies  map[string]interface{}
+	commands [][]interface{}
+}
+
+func (c *fakeValueConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.commands = append(c.commands, append([]interface{}{commandName}, args...))
+	if commandName == "CONFIG" && len(args) > 0 && args[0] == "SET" {
+		return "OK", nil
+	}
+	return c.replies[commandName], nil
+}
+
+func (c *fakeValueConn) Close() error { return nil }
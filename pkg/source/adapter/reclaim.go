@@ -0,0 +1,159 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	reclaimMinIdleTime = 5 * time.Minute // default time an entry must be pending before being claimed
+	reclaimInterval    = 1 * time.Minute // default time between two checks of the pending entries
+	reclaimCount       = 100             // maximum number of entries claimed at once
+)
+
+// reclaim periodically claims the entries that have been pending for too long,
+// for instance because their consumer crashed or was scaled down, and delivers
+// them again on behalf of consumerName.
+func (a *Adapter) reclaim(ctx context.Context, pool *redis.Pool, groupName string, consumerName string) {
+	ticker := time.NewTicker(a.reclaimInterval)
+	defer ticker.Stop()
+
+	useXAutoClaim := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			conn := pool.Get()
+			for _, streamName := range a.currentStreams() {
+				useXAutoClaim = a.reclaimStream(ctx, conn, streamName, groupName, consumerName, useXAutoClaim)
+			}
+			conn.Close()
+		}
+	}
+}
+
+// reclaimStream claims and delivers the stale pending entries of a stream. It
+// returns whether XAUTOCLAIM is supported.
+func (a *Adapter) reclaimStream(ctx context.Context, conn redis.Conn, streamName string, groupName string, consumerName string, useXAutoClaim bool) bool {
+	items, err := a.claimStaleEntries(conn, streamName, groupName, consumerName, useXAutoClaim)
+	if err != nil && useXAutoClaim && isUnknownCommand(err) {
+		a.logger.Info("XAUTOCLAIM is not supported, falling back to XPENDING and XCLAIM")
+		useXAutoClaim = false
+		items, err = a.claimStaleEntries(conn, streamName, groupName, consumerName, useXAutoClaim)
+	}
+	if err != nil {
+		a.logger.Error("Cannot claim pending messages", zap.String("stream", streamName), zap.Error(err))
+	}
+
+	attrs := a.attributes(StreamAttr.With(streamName), GroupAttr.With(groupName))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.FieldValues == nil {
+			// The entry was deleted while pending, there is nothing to deliver.
+			ids = append(ids, item.ID)
+			continue
+		}
+		if err := a.deliver(ctx, a.newEvent(streamName, item), attrs); err != nil {
+			// Stays pending and is claimed again later.
+			continue
+		}
+		ids = append(ids, item.ID)
+	}
+	if err := a.ack(ctx, conn, streamName, groupName, ids); err != nil {
+		a.logger.Error("Cannot ack messages", zap.String("stream", streamName), zap.Error(err))
+	}
+	if len(items) > 0 {
+		a.logger.Info("Reclaimed pending messages", zap.String("consumerName", consumerName), zap.String("stream", streamName), zap.Int("count", len(items)))
+	}
+	return useXAutoClaim
+}
+
+// claimStaleEntries claims the entries pending for longer than the minimum
+// idle time and reads them from the stream. The entries deleted while pending
+// are returned without field-value pairs, so that they are acknowledged. They
+// are claimed by ID since, before Redis 7.0, the claim commands reply a nil
+// entry without ID for them.
+func (a *Adapter) claimStaleEntries(conn redis.Conn, streamName string, groupName string, consumerName string, useXAutoClaim bool) ([]scan.StreamItem, error) {
+	ids, err := a.claimStaleIDs(conn, streamName, groupName, consumerName, useXAutoClaim)
+
+	items := make([]scan.StreamItem, 0, len(ids))
+	for _, id := range ids {
+		// XRANGE replies the entries like XCLAIM.
+		entries, rangeErr := scan.ScanXClaimReply(conn.Do("XRANGE", streamName, id, id))
+		if rangeErr != nil {
+			// The remaining entries are claimed again later.
+			return items, rangeErr
+		}
+		item := scan.StreamItem{ID: id}
+		if len(entries) > 0 {
+			item = entries[0]
+		}
+		items = append(items, item)
+	}
+	return items, err
+}
+
+// claimStaleIDs claims the entries pending for longer than the minimum idle
+// time and returns their IDs.
+func (a *Adapter) claimStaleIDs(conn redis.Conn, streamName string, groupName string, consumerName string, useXAutoClaim bool) ([]string, error) {
+	minIdleTime := a.reclaimMinIdleTime.Milliseconds()
+
+	if useXAutoClaim {
+		var claimed []string
+		cursor := "0-0"
+		for {
+			next, ids, err := scan.ScanXAutoClaimJustIDReply(conn.Do("XAUTOCLAIM", streamName, groupName, consumerName, minIdleTime, cursor, "COUNT", reclaimCount, "JUSTID"))
+			if err != nil {
+				return claimed, err
+			}
+			claimed = append(claimed, ids...)
+			if next == "0-0" || len(claimed) >= reclaimCount {
+				return claimed, nil
+			}
+			cursor = next
+		}
+	}
+
+	pending, err := scan.ScanXPendingReply(conn.Do("XPENDING", streamName, groupName, "-", "+", reclaimCount))
+	if err != nil {
+		return nil, err
+	}
+
+	args := []interface{}{streamName, groupName, consumerName, minIdleTime}
+	for _, p := range pending {
+		if int64(p.IdleTime) >= minIdleTime {
+			args = append(args, p.MessageID)
+		}
+	}
+	if len(args) == 4 {
+		return nil, nil
+	}
+	return redis.Strings(conn.Do("XCLAIM", append(args, "JUSTID")...))
+}
+
+func isUnknownCommand(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "unknown command")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func TestAdapter_ReclaimStream(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XAUTOCLAIM": []interface{}{
+				[]byte("0-0"),
+				[]interface{}{[]byte("1-0")},
+				[]interface{}{},
+			},
+			"XRANGE": []interface{}{
+				[]interface{}{[]byte("1-0"), []interface{}{[]byte("foo"), []byte("bar")}},
+			},
+			"XACK": int64(1),
+		},
+	}
+	client := &fakeClient{}
+	a := &Adapter{
+		config:             &Config{PodName: "mysource-0"},
+		logger:             zap.NewNop(),
+		client:             client,
+		metrics:            newMetrics(noop.NewMeterProvider()),
+		reclaimMinIdleTime: time.Minute,
+	}
+
+	useXAutoClaim := a.reclaimStream(context.Background(), conn, "mystream", "mygroup", "mysource-0-0", true)
+	require.True(t, useXAutoClaim)
+	require.Len(t, client.events, 1)
+	require.Equal(t, [][]interface{}{
+		{"XAUTOCLAIM", "mystream", "mygroup", "mysource-0-0", int64(60000), "0-0", "COUNT", reclaimCount, "JUSTID"},
+		{"XRANGE", "mystream", "1-0", "1-0"},
+		{"XACK", "mystream", "mygroup", "1-0"},
+	}, conn.commands)
+}
+
+func TestAdapter_ReclaimStreamDeletedEntries(t *testing.T) {
+	conn := &fakeConn{
+		replies: map[string]interface{}{
+			"XAUTOCLAIM": []interface{}{
+				[]byte("0-0"),
+				[]interface{}{[]byte("1-0"), []byte("2-0")},
+			},
+			"XRANGE": []interface{}{},
+			"XACK":   int64(2),
+		},
+	}
+	client := &fakeClient{}
+	a := &Adapter{
+		config:             &Config{PodName: "mysource-0"},
+		logger:             zap.NewNop(),
+		client:             client,
+		metrics:            newMetrics(noop.NewMeterProvider()),
+		reclaimMinIdleTime: time.Minute,
+	}
+
+	a.reclaimStream(context.Background(), conn, "mystream", "mygroup", "mysource-0-0", true)
+	require.Empty(t, client.events, "should not deliver deleted entries")
+	require.Equal(t, []interface{}{"XACK", "mystream", "mygroup", "1-0", "2-0"}, conn.commands[len(conn.commands)-1])
+}
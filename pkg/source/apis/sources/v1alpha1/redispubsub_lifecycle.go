@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// RedisPubSubConditionReady has status True when the RedisPubSubSource is ready to send events.
+	RedisPubSubConditionReady = apis.ConditionReady
+
+	// RedisPubSubConditionSinkProvided has status True when the RedisPubSubSource has been configured with a sink target.
+	RedisPubSubConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// RedisPubSubConditionDeployed has status True when the RedisPubSubSource has had it's deployment created.
+	RedisPubSubConditionDeployed apis.ConditionType = "Deployed"
+)
+
+var redisPubSubCondSet = apis.NewLivingConditionSet(
+	RedisPubSubConditionSinkProvided,
+	RedisPubSubConditionDeployed,
+)
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*RedisPubSubSource) GetConditionSet() apis.ConditionSet {
+	return redisPubSubCondSet
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (s *RedisPubSubSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("RedisPubSubSource")
+}
+
+// GetUntypedSpec returns the spec of the RedisPubSubSource.
+func (s *RedisPubSubSource) GetUntypedSpec() interface{} {
+	return s.Spec
+}
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *RedisPubSubSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return redisPubSubCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *RedisPubSubSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return redisPubSubCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *RedisPubSubSourceStatus) InitializeConditions() {
+	redisPubSubCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSink sets the condition that the source has a sink configured.
+func (s *RedisPubSubSourceStatus) MarkSink(uri string) {
+	s.SinkURI = nil
+	if len(uri) > 0 {
+		if u, err := apis.ParseURL(uri); err != nil {
+			redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionSinkProvided, "SinkInvalid", "Failed to parse sink: %v", err)
+		} else {
+			s.SinkURI = u
+			redisPubSubCondSet.Manage(s).MarkTrue(RedisPubSubConditionSinkProvided)
+		}
+	} else {
+		redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty.")
+	}
+}
+
+// MarkNoSink sets the condition that the source does not have a sink configured.
+func (s *RedisPubSubSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	redisPubSubCondSet.Manage(s).MarkFalse(RedisPubSubConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// PropagateDeploymentAvailability uses the availability of the provided Deployment to determine if
+// RedisPubSubConditionDeployed should be marked as true or false.
+func (s *RedisPubSubSourceStatus) PropagateDeploymentAvailability(d *appsv1.Deployment) {
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable && cond.Status == corev1.ConditionTrue {
+			redisPubSubCondSet.Manage(s).MarkTrue(RedisPubSubConditionDeployed)
+			return
+		}
+	}
+	redisPubSubCondSet.Manage(s).MarkUnknown(RedisPubSubConditionDeployed, "DeploymentUnavailable", "The Deployment '%s' is unavailable.", d.Name)
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *RedisPubSubSourceStatus) IsReady() bool {
+	return redisPubSubCondSet.Manage(s).IsHappy()
+}
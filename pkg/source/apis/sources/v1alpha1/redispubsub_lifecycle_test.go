@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	availableDeployment = &appsv1.Deployment{
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentAvailable,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+	unavailableDeployment = &appsv1.Deployment{
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentAvailable,
+				Status: corev1.ConditionFalse,
+			}},
+		},
+	}
+)
+
+var _ = duck.VerifyType(&RedisPubSubSource{}, &duckv1.Conditions{})
+
+func TestRedisPubSubSourceGetConditionSet(t *testing.T) {
+	r := &RedisPubSubSource{}
+
+	if got, want := r.GetConditionSet().GetTopLevelConditionType(), apis.ConditionReady; got != want {
+		t.Errorf("GetTopLevelCondition=%v, want=%v", got, want)
+	}
+}
+
+func TestRedisPubSubSourceStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *RedisPubSubSourceStatus
+		want bool
+	}{{
+		name: "uninitialized",
+		s:    &RedisPubSubSourceStatus{},
+		want: false,
+	}, {
+		name: "initialized",
+		s: func() *RedisPubSubSourceStatus {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "mark sink",
+		s: func() *RedisPubSubSourceStatus {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "mark sink and unavailable deployment",
+		s: func() *RedisPubSubSourceStatus {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateDeploymentAvailability(unavailableDeployment)
+			return s
+		}(),
+		want: false,
+	}, {
+		name: "mark sink and deployed",
+		s: func() *RedisPubSubSourceStatus {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateDeploymentAvailability(availableDeployment)
+			return s
+		}(),
+		want: true,
+	}, {
+		name: "no sink and deployed",
+		s: func() *RedisPubSubSourceStatus {
+			s := &RedisPubSubSourceStatus{}
+			s.InitializeConditions()
+			s.MarkNoSink("NotFound", "")
+			s.PropagateDeploymentAvailability(availableDeployment)
+			return s
+		}(),
+		want: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.s.IsReady(); got != test.want {
+				t.Errorf("%s: IsReady() = %v, want %v", test.name, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:defaulter-gen=true
+
+// RedisPubSubSource is the Schema for the RedisPubSub API.
+type RedisPubSubSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RedisPubSubSourceSpec   `json:"spec,omitempty"`
+	Status RedisPubSubSourceStatus `json:"status,omitempty"`
+}
+
+// Check the interfaces that RedisPubSubSource should be implementing.
+var (
+	_ runtime.Object     = (*RedisPubSubSource)(nil)
+	_ kmeta.OwnerRefable = (*RedisPubSubSource)(nil)
+	_ apis.HasSpec       = (*RedisPubSubSource)(nil)
+	_ duckv1.KRShaped    = (*RedisPubSubSource)(nil)
+)
+
+// RedisPubSubSourceSpec defines the desired state of the RedisPubSubSource.
+type RedisPubSubSourceSpec struct {
+	// inherits duck/v1 SourceSpec, which currently provides:
+	// * Sink - a reference to an object that will resolve to a domain name or
+	//   a URI directly to use as the sink.
+	// * CloudEventOverrides - defines overrides to control the output format
+	//   and modifications of the event sent to the sink.
+	duckv1.SourceSpec `json:",inline"`
+
+	// RedisConnection represents the address and options to connect
+	// to a Redis instance
+	apisv1alpha1.RedisConnection `json:",inline"`
+
+	// Channels are the names of the channels to subscribe to.
+	// +optional
+	Channels []string `json:"channels,omitempty"`
+
+	// Patterns are the glob-style patterns of the channels to subscribe to.
+	// +optional
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Keyspace subscribes to the keyspace notifications of the database of
+	// the address, sending an event whenever a key changes.
+	// +optional
+	Keyspace *KeyspaceSpec `json:"keyspace,omitempty"`
+}
+
+// KeyspaceSpec defines which keyspace notifications are sent.
+type KeyspaceSpec struct {
+	// Notifications is the kind of notification channels subscribed to,
+	// Keyspace for the __keyspace@<db>__:<key> channels, or Keyevent for
+	// the __keyevent@<db>__:<operation> channels. Keyevent notifications
+	// are enabled with the E flag of notify-keyspace-events instead of the
+	// K flag. Defaults to Keyspace.
+	// +optional
+	Notifications string `json:"notifications,omitempty"`
+
+	// KeyPrefix only sends the changes of the keys starting with the prefix.
+	// Defaults to all the keys.
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// Operations only sends the changes made by these operations, for
+	// instance "set", "del" or "expired". Defaults to all the operations.
+	// +optional
+	Operations []string `json:"operations,omitempty"`
+
+	// Configure enables the keyspace notifications of all the operations
+	// with CONFIG SET notify-keyspace-events. Otherwise, the receive adapter
+	// only checks that they are enabled.
+	// +optional
+	Configure bool `json:"configure,omitempty"`
+
+	// FetchValue sends the value of the key, read when the notification is
+	// received, as data of the events.
+	// +optional
+	FetchValue bool `json:"fetchValue,omitempty"`
+}
+
+const (
+	// KeyspaceNotifications subscribes to the __keyspace@<db>__:<key>
+	// channels, where the operations changing the keys are published.
+	KeyspaceNotifications = "Keyspace"
+
+	// KeyeventNotifications subscribes to the __keyevent@<db>__:<operation>
+	// channels, where the keys changed by the operations are published.
+	KeyeventNotifications = "Keyevent"
+)
+
+// RedisPubSubSourceStatus defines the observed state of RedisPubSubSource.
+type RedisPubSubSourceStatus struct {
+	// inherits duck/v1 SourceStatus, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last
+	//   processed by the controller.
+	// * Conditions - the latest available observations of a resource's current
+	//   state.
+	// * SinkURI - the current active sink URI that has been configured for the
+	//   Source.
+	duckv1.SourceStatus `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RedisPubSubSourceList contains a list of RedisPubSubSources.
+type RedisPubSubSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RedisPubSubSource `json:"items"`
+}
+
+// GetStatus retrieves the status of the RedisPubSubSource. Implements the KRShaped interface.
+func (p *RedisPubSubSource) GetStatus() *duckv1.Status {
+	return &p.Status.Status
+}
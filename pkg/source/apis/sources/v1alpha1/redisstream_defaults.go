@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
+)
+
+// SetDefaults implements apis.Defaultable.
+func (s *RedisStreamSource) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+	s.Spec.SetDefaults(ctx)
+}
+
+// SetDefaults runs a single consumer unless told otherwise, and defaults the
+// namespace of the sinks to the namespace of the source. New sources keep
+// their consumer groups until they are deleted, which is also how the
+// existing ones without a group retention behave.
+func (s *RedisStreamSourceSpec) SetDefaults(ctx context.Context) {
+	if s.Consumers == nil {
+		s.Consumers = ptr.Int32(1)
+	}
+	if s.GroupRetention == "" && s.List == "" && apis.IsInCreate(ctx) {
+		s.GroupRetention = GroupRetentionDeleteOnSourceDeletion
+	}
+	s.Sink.SetDefaults(ctx)
+	s.Delivery.SetDefaults(ctx)
+}
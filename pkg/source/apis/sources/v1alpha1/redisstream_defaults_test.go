@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestRedisStreamSource_SetDefaults(t *testing.T) {
+	source := &RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "ns"},
+		Spec: RedisStreamSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{Ref: &duckv1.KReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "event-display",
+				}},
+			},
+		},
+	}
+	source.SetDefaults(context.Background())
+
+	if got := source.Spec.Consumers; got == nil || *got != 1 {
+		t.Errorf("Expected 1 consumer by default, got %v", got)
+	}
+	if got := source.Spec.Sink.Ref.Namespace; got != "ns" {
+		t.Errorf("Expected the sink in the namespace of the source, got %q", got)
+	}
+
+	if got := source.Spec.GroupRetention; got != "" {
+		t.Errorf("Expected the group retention of an existing source to be kept, got %q", got)
+	}
+
+	source.Spec.Consumers = ptr.Int32(0)
+	source.SetDefaults(context.Background())
+	if got := *source.Spec.Consumers; got != 0 {
+		t.Errorf("Expected the explicit number of consumers to be kept, got %d", got)
+	}
+
+	source.SetDefaults(apis.WithinCreate(context.Background()))
+	if got := source.Spec.GroupRetention; got != GroupRetentionDeleteOnSourceDeletion {
+		t.Errorf("Expected new sources to keep their groups until deleted, got %q", got)
+	}
+}
@@ -31,6 +31,15 @@ const (
 
 	// RedisStreamConditionDeployed has status True when the RedisStreamSource has had it's statefulset created.
 	RedisStreamConditionDeployed apis.ConditionType = "Deployed"
+
+	// RedisStreamConditionStreamReady has status True when the streams of the RedisStreamSource and their
+	// consumer groups exist. It does not affect the readiness of the source, which keeps delivering while
+	// the controller cannot read the streams.
+	RedisStreamConditionStreamReady apis.ConditionType = "StreamReady"
+
+	// RedisStreamConditionStartFromApplied has status True when the start position of the RedisStreamSource
+	// has been applied to its existing consumer groups. It does not affect the readiness of the source.
+	RedisStreamConditionStartFromApplied apis.ConditionType = "StartFromApplied"
 )
 
 var redisStreamCondSet = apis.NewLivingConditionSet(
@@ -89,6 +98,17 @@ func (s *RedisStreamSourceStatus) MarkNoSink(reason, messageFormat string, messa
 	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionSinkProvided, reason, messageFormat, messageA...)
 }
 
+// MarkDeadLetterSink sets the resolved dead letter sink URI. A nil uri clears it.
+func (s *RedisStreamSourceStatus) MarkDeadLetterSink(uri *apis.URL) {
+	s.DeadLetterSinkURI = uri
+}
+
+// MarkNoDeadLetterSink sets the condition that the source dead letter sink could not be resolved.
+func (s *RedisStreamSourceStatus) MarkNoDeadLetterSink(reason, messageFormat string, messageA ...interface{}) {
+	s.DeadLetterSinkURI = nil
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
 // PropagateStatefulSetAvailability uses the availability of the provided StatefulSet to determine if
 // RedisStreamConditionDeployed should be marked as true or false.
 func (s *RedisStreamSourceStatus) PropagateStatefulSetAvailability(d *appsv1.StatefulSet) {
@@ -102,6 +122,33 @@ func (s *RedisStreamSourceStatus) PropagateStatefulSetAvailability(d *appsv1.Sta
 	}
 }
 
+// MarkStreamReady sets the condition that the streams of the source and their consumer groups exist.
+func (s *RedisStreamSourceStatus) MarkStreamReady() {
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionStreamReady)
+}
+
+// MarkStreamNotReady sets the condition that a stream of the source or its consumer group is missing.
+func (s *RedisStreamSourceStatus) MarkStreamNotReady(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionStreamReady, reason, messageFormat, messageA...)
+}
+
+// MarkStreamUnknown sets the condition that the streams of the source could not be read from Redis.
+func (s *RedisStreamSourceStatus) MarkStreamUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.Streams = nil
+	redisStreamCondSet.Manage(s).MarkUnknown(RedisStreamConditionStreamReady, reason, messageFormat, messageA...)
+}
+
+// MarkStartFromApplied sets the condition that the start position was applied to the existing consumer groups.
+func (s *RedisStreamSourceStatus) MarkStartFromApplied(startFrom string) {
+	s.StartFrom = startFrom
+	redisStreamCondSet.Manage(s).MarkTrue(RedisStreamConditionStartFromApplied)
+}
+
+// MarkStartFromNotApplied sets the condition that the start position could not be applied to the existing consumer groups.
+func (s *RedisStreamSourceStatus) MarkStartFromNotApplied(reason, messageFormat string, messageA ...interface{}) {
+	redisStreamCondSet.Manage(s).MarkFalse(RedisStreamConditionStartFromApplied, reason, messageFormat, messageA...)
+}
+
 // IsReady returns true if the resource is ready overall.
 func (s *RedisStreamSourceStatus) IsReady() bool {
 	return redisStreamCondSet.Manage(s).IsHappy()
@@ -103,6 +103,51 @@ func TestRedisStreamSourceStatusIsReady(t *testing.T) {
 			return s
 		}(),
 		want: false,
+	}, {
+		name: "mark sink, deployed and stream ready",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamReady()
+			return s
+		}(),
+		want: true,
+	}, {
+		name: "mark sink, deployed and stream ready, start position not applied",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamReady()
+			s.MarkStartFromNotApplied("RedisUnavailable", "Cannot reach Redis.")
+			return s
+		}(),
+		want: true,
+	}, {
+		name: "mark sink and deployed, streams unreadable",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamUnknown("RedisUnavailable", "Cannot read the streams.")
+			return s
+		}(),
+		want: true,
+	}, {
+		name: "mark sink and deployed, stream not ready",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamNotReady("StreamNotFound", "The stream %q does not exist.", "mystream")
+			return s
+		}(),
+		want: true,
 	}}
 
 	for _, test := range tests {
@@ -165,6 +210,39 @@ func TestRedisStreamSourceStatusGetCondition(t *testing.T) {
 			Type:   RedisStreamConditionReady,
 			Status: corev1.ConditionTrue,
 		},
+	}, {
+		name: "mark sink, deployed and stream ready",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamReady()
+			return s
+		}(),
+		condQuery: RedisStreamConditionReady,
+		want: &apis.Condition{
+			Type:   RedisStreamConditionReady,
+			Status: corev1.ConditionTrue,
+		},
+	}, {
+		name: "mark sink, deployed, then stream not ready",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkStreamReady()
+			s.MarkStreamNotReady("GroupNotFound", "The consumer group %q does not exist.", "mygroup")
+			return s
+		}(),
+		condQuery: RedisStreamConditionStreamReady,
+		want: &apis.Condition{
+			Type:    RedisStreamConditionStreamReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "GroupNotFound",
+			Message: `The consumer group "mygroup" does not exist.`,
+		},
 	}, {
 		name: "mark sink, rolebinding, then no sink",
 		s: func() *RedisStreamSourceStatus {
@@ -182,6 +260,23 @@ func TestRedisStreamSourceStatusGetCondition(t *testing.T) {
 			Reason:  "Testing",
 			Message: "hi",
 		},
+	}, {
+		name: "mark sink, deployed, then no dead letter sink",
+		s: func() *RedisStreamSourceStatus {
+			s := &RedisStreamSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example").String())
+			s.PropagateStatefulSetAvailability(availableStatefulSet)
+			s.MarkNoDeadLetterSink("DeadLetterSinkNotFound", "not found")
+			return s
+		}(),
+		condQuery: RedisStreamConditionReady,
+		want: &apis.Condition{
+			Type:    RedisStreamConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "DeadLetterSinkNotFound",
+			Message: "not found",
+		},
 	}}
 
 	for _, test := range tests {
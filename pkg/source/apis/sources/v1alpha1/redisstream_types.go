@@ -17,12 +17,17 @@ limitations under the License.
 package v1alpha1
 
 import (
-	corev1 "k8s.io/api/core/v1"
+	"time"
+
+	"github.com/rickb777/date/period"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
 )
 
 // +genclient
@@ -43,10 +48,10 @@ type RedisStreamSource struct {
 var (
 	_ runtime.Object     = (*RedisStreamSource)(nil)
 	_ kmeta.OwnerRefable = (*RedisStreamSource)(nil)
-	//_ apis.Validatable   = (*RedisStreamSource)(nil)
-	//_ apis.Defaultable   = (*RedisStreamSource)(nil)
-	_ apis.HasSpec    = (*RedisStreamSource)(nil)
-	_ duckv1.KRShaped = (*RedisStreamSource)(nil)
+	_ apis.Validatable   = (*RedisStreamSource)(nil)
+	_ apis.Defaultable   = (*RedisStreamSource)(nil)
+	_ apis.HasSpec       = (*RedisStreamSource)(nil)
+	_ duckv1.KRShaped    = (*RedisStreamSource)(nil)
 )
 
 // RedisStreamSourceSpec defines the desired state of the RedisStreamSource.
@@ -60,66 +65,223 @@ type RedisStreamSourceSpec struct {
 
 	// RedisConnection represents the address and options to connect
 	// to a Redis instance
-	RedisConnection `json:",inline"`
+	apisv1alpha1.RedisConnection `json:",inline"`
 
-	// Stream is the name of the stream.
+	// Stream is the name of the stream. Either a stream, a stream pattern
+	// or the list must be set.
 	Stream string `json:"stream"`
 
+	// Streams are the names of additional streams, read along with the
+	// stream in a single XREADGROUP. Each stream has a consumer group of
+	// the same name, and the events carry the name of their stream in the
+	// redisstream extension.
+	// +optional
+	Streams []string `json:"streams,omitempty"`
+
+	// StreamPattern is a glob-style pattern of the keys of streams that are
+	// discovered periodically and read along with the other streams. Each
+	// stream found gets a consumer group, and streams that disappear are no
+	// longer read. Requires Redis 6.0 or later, and is not supported in
+	// cluster mode.
+	// +optional
+	StreamPattern string `json:"streamPattern,omitempty"`
+
+	// DiscoveryInterval is how often the streams matching the stream pattern
+	// are discovered, as an ISO-8601 duration. Defaults to PT30S.
+	// +optional
+	DiscoveryInterval *string `json:"discoveryInterval,omitempty"`
+
+	// List is the name of a list consumed as a reliable queue instead of a
+	// stream. Each consumer moves the items pushed on the list to its own
+	// processing list, removing them once delivered. The items of consumers
+	// gone for longer than the reclaim minimum idle time are requeued. The
+	// options of the consumer groups of the streams, the group, group
+	// retention, start position, batch size and discovery interval, must not
+	// be set with a list.
+	// +optional
+	List string `json:"list,omitempty"`
+
 	// Group is the name of the consumer group associated to this source.
-	// When left empty, a group is automatically created for this source and
-	// deleted when this source is deleted.
+	// When left empty, a group is automatically created for this source.
 	// +optional
 	Group string `json:"group,omitempty"`
 
+	// GroupRetention is when the consumer groups of this source are
+	// destroyed, either "Delete", "Retain" or "DeleteOnSourceDeletion".
+	// Defaults to "DeleteOnSourceDeletion".
+	// +optional
+	GroupRetention string `json:"groupRetention,omitempty"`
+
+	// StartFrom is the position from which new consumer groups read the
+	// stream: "0" for the beginning, "$" for new entries only, an entry ID,
+	// or an RFC 3339 timestamp. Defaults to "$". Changing it resets the
+	// position of the existing consumer groups.
+	// +optional
+	StartFrom *string `json:"startFrom,omitempty"`
+
 	// Number of desired consumers running in the consumer group. Defaults to 1.
+	// Ignored when autoscaling is set.
 	//
 	// This is a pointer to distinguish between explicit
 	// zero and not specified.
 	// +optional
 	Consumers *int32 `json:"consumers,omitempty"`
-}
 
-// RedisConnection defines the address and options to connect to a Redis instance
-type RedisConnection struct {
-	// Address is the Redis TCP address
-	Address string `json:"address"`
+	// Autoscaling scales the consumers with the backlog of the consumer
+	// group, instead of running a fixed number of consumers. Requires a
+	// group.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
 
-	// Options are the connection options
+	// BatchSize is the maximum number of entries each consumer reads from
+	// the stream at once. Defaults to 1.
 	// +optional
-	Options *RedisConnectionOptions `json:"dialOptions,omitempty"`
-}
+	BatchSize *int32 `json:"batchSize,omitempty"`
 
-// RedisConnection defines the desired state of the RedisStreamSource.
-type RedisConnectionOptions struct {
-	// Password to use for connecting to Redis
+	// BlockMilliseconds is how long each read blocks waiting for new entries
+	// before timing out. Defaults to 5000.
 	// +optional
-	Password corev1.ObjectReference `json:"password,omitempty"`
+	BlockMilliseconds *int32 `json:"blockMilliseconds,omitempty"`
 
-	// UseTLS indicates whether to use TLS or not
+	// Encoding is how events are read from the stream entries, either
+	// "array" or "cloudevents". Defaults to "array".
 	// +optional
-	UseTLS bool `json:"useTLS,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
 
-	// SkipVerify indicates whether to skip TLS verification or not
+	// Delivery contains the retry and dead letter sink options used when
+	// sending events to the sink. Entries are only acknowledged once the
+	// event has been delivered to the sink or to the dead letter sink.
 	// +optional
-	SkipVerify bool `json:"skipVerify,omitempty"`
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
 
-	// Cert is the Kubernetes secret containing the client certificate.
+	// Reclaim configures how entries left pending by consumers that are
+	// gone, for instance after a crash or a scale down, are claimed and
+	// delivered again.
 	// +optional
-	Cert RedisSecretValueFromSource `json:"cert,omitempty"`
+	Reclaim *ReclaimSpec `json:"reclaim,omitempty"`
+}
+
+const (
+	// EncodingArray sends events whose data is the JSON array of the entry
+	// field/value pairs.
+	EncodingArray = "array"
 
-	// Key is the Kubernetes secret containing the client key.
+	// EncodingCloudEvents rebuilds the events stored with their attributes,
+	// as CE prefixed fields or a structured mode field, by a RedisStreamSink.
+	// Other entries are read with the array encoding.
+	EncodingCloudEvents = "cloudevents"
+)
+
+const (
+	// GroupRetentionDelete destroys the consumer groups whenever the receive
+	// adapter shuts down, for instance during a rolling update.
+	GroupRetentionDelete = "Delete"
+
+	// GroupRetentionRetain never destroys the consumer groups.
+	GroupRetentionRetain = "Retain"
+
+	// GroupRetentionDeleteOnSourceDeletion destroys the consumer groups when
+	// the source is deleted.
+	GroupRetentionDeleteOnSourceDeletion = "DeleteOnSourceDeletion"
+)
+
+// GetGroupRetention returns the group retention, "DeleteOnSourceDeletion"
+// when it is not set.
+func (s *RedisStreamSourceSpec) GetGroupRetention() string {
+	if s.GroupRetention == "" {
+		return GroupRetentionDeleteOnSourceDeletion
+	}
+	return s.GroupRetention
+}
+
+// StreamNames returns the names of the streams consumed by the source, the
+// stream followed by the additional streams, without duplicates.
+func (s *RedisStreamSourceSpec) StreamNames() []string {
+	names := make([]string, 0, len(s.Streams)+1)
+	seen := make(map[string]bool, len(s.Streams)+1)
+	for _, name := range append([]string{s.Stream}, s.Streams...) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ReclaimSpec defines how stale pending entries are reclaimed.
+type ReclaimSpec struct {
+	// MinIdleTime is how long an entry must have been pending before it is
+	// claimed by another consumer, as an ISO-8601 duration. Defaults to PT5M.
 	// +optional
-	Key RedisSecretValueFromSource `json:"key,omitempty"`
+	MinIdleTime *string `json:"minIdleTime,omitempty"`
 
-	// CACert is the Kubernetes secret containing the server CA cert.
+	// Interval is how often pending entries are checked, as an ISO-8601
+	// duration. Defaults to PT1M.
 	// +optional
-	CACert RedisSecretValueFromSource `json:"caCert,omitempty"`
+	Interval *string `json:"interval,omitempty"`
 }
 
-// RedisSecretValueFromSource represents the source of a secret value
-type RedisSecretValueFromSource struct {
-	// The Secret key to select from.
-	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+// AutoscalingSpec defines how the consumers are scaled with the backlog of
+// the consumer group, the entries pending or not delivered yet.
+type AutoscalingSpec struct {
+	// MinReplicas is the minimum number of consumers. Zero stops all the
+	// consumers while the streams are idle, until new entries are added.
+	// Defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of consumers.
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetPending is the number of entries of the backlog each consumer
+	// should have. Defaults to 100.
+	// +optional
+	TargetPending *int64 `json:"targetPending,omitempty"`
+
+	// Cooldown is how long to wait after scaling before scaling down, as an
+	// ISO-8601 duration. Defaults to PT5M.
+	// +optional
+	Cooldown *string `json:"cooldown,omitempty"`
+}
+
+const (
+	// DefaultTargetPending is the default number of entries of the backlog
+	// of each consumer.
+	DefaultTargetPending = 100
+
+	// DefaultCooldown is the default time to wait before scaling down.
+	DefaultCooldown = 5 * time.Minute
+)
+
+// GetMinReplicas returns the minimum number of consumers, 1 by default.
+func (a *AutoscalingSpec) GetMinReplicas() int32 {
+	if a.MinReplicas == nil {
+		return 1
+	}
+	return *a.MinReplicas
+}
+
+// GetTargetPending returns the number of entries of the backlog of each
+// consumer, DefaultTargetPending by default.
+func (a *AutoscalingSpec) GetTargetPending() int64 {
+	if a.TargetPending == nil {
+		return DefaultTargetPending
+	}
+	return *a.TargetPending
+}
+
+// GetCooldown returns the time to wait before scaling down, DefaultCooldown
+// by default or when the cooldown is invalid.
+func (a *AutoscalingSpec) GetCooldown() time.Duration {
+	if a.Cooldown == nil {
+		return DefaultCooldown
+	}
+	p, err := period.Parse(*a.Cooldown)
+	if err != nil {
+		return DefaultCooldown
+	}
+	d, _ := p.Duration()
+	return d
 }
 
 // RedisStreamSourceStatus defines the observed state of RedisStreamSource.
@@ -133,9 +295,91 @@ type RedisStreamSourceStatus struct {
 	//   Source.
 	duckv1.SourceStatus `json:",inline"`
 
+	// DeliveryStatus contains the resolved dead letter sink URI, if any.
+	// +optional
+	eventingduckv1.DeliveryStatus `json:",inline"`
+
+	// StartFrom is the start position last applied to the consumer groups.
+	// +optional
+	StartFrom string `json:"startFrom,omitempty"`
+
 	// Total number of consumers actually running in the consumer group.
 	// +optional
 	Consumers int32 `json:"consumers,omitempty"`
+
+	// Streams reports the state of the streams read by the source and of
+	// their consumer groups, as last read from Redis.
+	// +optional
+	Streams []RedisStreamStatus `json:"streams,omitempty"`
+
+	// Autoscaling reports the state of the autoscaling of the consumers.
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+}
+
+// AutoscalingStatus is the state of the autoscaling of the consumers.
+type AutoscalingStatus struct {
+	// Backlog is the number of entries pending or not delivered yet to the
+	// consumer group, as last read from Redis.
+	Backlog int64 `json:"backlog"`
+
+	// Replicas is the number of consumers decided by the autoscaling.
+	Replicas int32 `json:"replicas"`
+
+	// LastScaleTime is when the number of consumers last changed.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// RedisStreamStatus is the state of a stream read by a RedisStreamSource.
+type RedisStreamStatus struct {
+	// Name is the name of the stream.
+	Name string `json:"name"`
+
+	// Length is the number of entries in the stream.
+	Length int64 `json:"length"`
+
+	// Groups are the consumer groups of the source on the stream.
+	// +optional
+	Groups []RedisStreamGroupStatus `json:"groups,omitempty"`
+}
+
+// RedisStreamGroupStatus is the state of a consumer group of a RedisStreamSource.
+type RedisStreamGroupStatus struct {
+	// Name is the name of the consumer group.
+	Name string `json:"name"`
+
+	// Pending is the number of entries delivered to the group but not
+	// acknowledged yet.
+	Pending int64 `json:"pending"`
+
+	// Lag is the number of entries of the stream not delivered to the group
+	// yet. Before Redis 7.0, it is only known when all the entries were
+	// delivered.
+	// +optional
+	Lag *int64 `json:"lag,omitempty"`
+
+	// LastDeliveredID is the ID of the last entry delivered to the group.
+	// +optional
+	LastDeliveredID string `json:"lastDeliveredId,omitempty"`
+
+	// Consumers are the consumers of the group.
+	// +optional
+	Consumers []RedisStreamConsumerStatus `json:"consumers,omitempty"`
+}
+
+// RedisStreamConsumerStatus is the state of a consumer of a consumer group.
+type RedisStreamConsumerStatus struct {
+	// Name is the name of the consumer.
+	Name string `json:"name"`
+
+	// Pending is the number of entries delivered to the consumer but not
+	// acknowledged yet.
+	Pending int64 `json:"pending"`
+
+	// IdleMilliseconds is the time since the consumer last interacted with
+	// Redis, rounded down to the interval at which the status is read.
+	IdleMilliseconds int64 `json:"idleMilliseconds"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -18,9 +18,11 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 )
 
 func TestRedisStreamSourceGetStatus(t *testing.T) {
@@ -44,3 +46,68 @@ func TestRedisStreamSource_GetGroupVersionKind(t *testing.T) {
 		t.Errorf("Should be 'RedisStreamSource'.")
 	}
 }
+
+func TestRedisStreamSourceSpec_GetGroupRetention(t *testing.T) {
+	spec := RedisStreamSourceSpec{}
+	if got := spec.GetGroupRetention(); got != GroupRetentionDeleteOnSourceDeletion {
+		t.Errorf("Expected default group retention %q, got %q", GroupRetentionDeleteOnSourceDeletion, got)
+	}
+
+	spec.GroupRetention = GroupRetentionRetain
+	if got := spec.GetGroupRetention(); got != GroupRetentionRetain {
+		t.Errorf("Expected group retention %q, got %q", GroupRetentionRetain, got)
+	}
+}
+
+func TestRedisStreamSourceSpec_StreamNames(t *testing.T) {
+	tests := []struct {
+		name string
+		spec RedisStreamSourceSpec
+		want []string
+	}{{
+		name: "stream",
+		spec: RedisStreamSourceSpec{Stream: "orders"},
+		want: []string{"orders"},
+	}, {
+		name: "streams",
+		spec: RedisStreamSourceSpec{Streams: []string{"tenant-a", "tenant-b"}},
+		want: []string{"tenant-a", "tenant-b"},
+	}, {
+		name: "stream and duplicated streams",
+		spec: RedisStreamSourceSpec{Stream: "tenant-a", Streams: []string{"tenant-a", "tenant-b", "tenant-b"}},
+		want: []string{"tenant-a", "tenant-b"},
+	}, {
+		name: "list",
+		spec: RedisStreamSourceSpec{List: "jobs"},
+		want: []string{},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.spec.StreamNames()); diff != "" {
+				t.Error("unexpected stream names (-want, +got) =", diff)
+			}
+		})
+	}
+}
+
+func TestAutoscalingSpec_Defaults(t *testing.T) {
+	spec := AutoscalingSpec{MaxReplicas: 10}
+	if got := spec.GetMinReplicas(); got != 1 {
+		t.Errorf("Expected default min replicas 1, got %d", got)
+	}
+	if got := spec.GetTargetPending(); got != DefaultTargetPending {
+		t.Errorf("Expected default target pending %d, got %d", DefaultTargetPending, got)
+	}
+	if got := spec.GetCooldown(); got != DefaultCooldown {
+		t.Errorf("Expected default cooldown %v, got %v", DefaultCooldown, got)
+	}
+
+	spec.MinReplicas = ptr.Int32(0)
+	spec.Cooldown = ptr.String("PT30S")
+	if got := spec.GetMinReplicas(); got != 0 {
+		t.Errorf("Expected min replicas 0, got %d", got)
+	}
+	if got := spec.GetCooldown(); got != 30*time.Second {
+		t.Errorf("Expected cooldown 30s, got %v", got)
+	}
+}
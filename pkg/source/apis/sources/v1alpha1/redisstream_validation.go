@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+	"knative.dev/eventing-redis/pkg/redisconn"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// Validate implements apis.Validatable.
+func (s *RedisStreamSource) Validate(ctx context.Context) *apis.FieldError {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate checks the connection, the streams or the list consumed, the sink
+// and the read options.
+func (s *RedisStreamSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := s.SourceSpec.Validate(ctx)
+	errs = errs.Also(s.RedisConnection.Validate(ctx))
+
+	streams := s.Stream != "" || len(s.Streams) > 0 || s.StreamPattern != ""
+	switch {
+	case !streams && s.List == "":
+		errs = errs.Also(apis.ErrMissingOneOf("stream", "streams", "streamPattern", "list"))
+	case streams && s.List != "":
+		errs = errs.Also(apis.ErrGeneric("expected either streams or a list, got both", "stream", "streams", "streamPattern", "list"))
+	}
+	if s.List != "" {
+		errs = errs.Also(s.validateListOptions())
+	}
+	for i, name := range s.Streams {
+		if name == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(name, "streams", i))
+		}
+	}
+	if s.StreamPattern != "" && s.Cluster != nil {
+		errs = errs.Also(apis.ErrGeneric("stream discovery is not supported in cluster mode", "streamPattern", "cluster"))
+	}
+	if s.Cluster != nil && !redisconn.SameSlot(s.StreamNames()...) {
+		// The streams are read with a single XREADGROUP.
+		errs = errs.Also(apis.ErrGeneric("the streams must share a hash slot in cluster mode, for instance with a {hash tag} in their names", "stream", "streams", "cluster"))
+	}
+	if s.DiscoveryInterval != nil {
+		errs = errs.Also(apisv1alpha1.ValidateDuration(*s.DiscoveryInterval, "discoveryInterval"))
+	}
+
+	switch s.GroupRetention {
+	case "", GroupRetentionDelete, GroupRetentionRetain, GroupRetentionDeleteOnSourceDeletion:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.GroupRetention, "groupRetention"))
+	}
+	if s.StartFrom != nil {
+		if _, err := scan.StartID(*s.StartFrom); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*s.StartFrom, "startFrom", "expected 0, $, an entry ID or an RFC 3339 timestamp"))
+		}
+	}
+
+	if s.Consumers != nil && *s.Consumers < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.Consumers, "consumers", "expected a positive number or zero"))
+	}
+	if s.BatchSize != nil && *s.BatchSize < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.BatchSize, "batchSize", "expected a positive number"))
+	}
+	if s.BlockMilliseconds != nil && *s.BlockMilliseconds < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.BlockMilliseconds, "blockMilliseconds", "expected a positive number"))
+	}
+	switch s.Encoding {
+	case "", EncodingArray, EncodingCloudEvents:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.Encoding, "encoding"))
+	}
+
+	if s.Autoscaling != nil {
+		// Scaling adds and removes consumers of a single group, which is
+		// destroyed by each consumer shutting down with the Delete retention.
+		if s.List != "" {
+			errs = errs.Also(apis.ErrGeneric("autoscaling is not supported for lists", "autoscaling", "list"))
+		} else if s.Group == "" {
+			errs = errs.Also(apis.ErrGeneric("autoscaling requires a group", "autoscaling", "group"))
+		}
+		if s.List == "" && s.GetGroupRetention() == GroupRetentionDelete {
+			errs = errs.Also(apis.ErrGeneric("autoscaling requires the consumer group to be retained when consumers shut down", "autoscaling", "groupRetention"))
+		}
+	}
+
+	errs = errs.Also(s.Delivery.Validate(ctx).ViaField("delivery"))
+	errs = errs.Also(s.Autoscaling.Validate(ctx).ViaField("autoscaling"))
+	return errs.Also(s.Reclaim.Validate(ctx).ViaField("reclaim"))
+}
+
+// validateListOptions checks that the options of the consumer groups of the
+// streams are not set along with the list, which is not read with consumer
+// groups.
+func (s *RedisStreamSourceSpec) validateListOptions() *apis.FieldError {
+	var fields []string
+	if s.Group != "" {
+		fields = append(fields, "group")
+	}
+	if s.GroupRetention != "" {
+		fields = append(fields, "groupRetention")
+	}
+	if s.StartFrom != nil {
+		fields = append(fields, "startFrom")
+	}
+	if s.BatchSize != nil {
+		fields = append(fields, "batchSize")
+	}
+	if s.DiscoveryInterval != nil {
+		fields = append(fields, "discoveryInterval")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	err := apis.ErrDisallowedFields(fields...)
+	err.Details = "the stream options do not apply to lists"
+	return err
+}
+
+// Validate checks the bounds of the number of consumers, the target and the
+// cooldown.
+func (a *AutoscalingSpec) Validate(ctx context.Context) *apis.FieldError {
+	if a == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if a.MaxReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(a.MaxReplicas, "maxReplicas", "expected a positive number"))
+	}
+	if a.MinReplicas != nil && (*a.MinReplicas < 0 || *a.MinReplicas > a.MaxReplicas) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*a.MinReplicas, 0, a.MaxReplicas, "minReplicas"))
+	}
+	if a.TargetPending != nil && *a.TargetPending < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*a.TargetPending, "targetPending", "expected a positive number"))
+	}
+	if a.Cooldown != nil {
+		errs = errs.Also(apisv1alpha1.ValidateDuration(*a.Cooldown, "cooldown"))
+	}
+	return errs
+}
+
+// Validate checks that the reclaim durations are positive.
+func (r *ReclaimSpec) Validate(ctx context.Context) *apis.FieldError {
+	if r == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	if r.MinIdleTime != nil {
+		errs = errs.Also(apisv1alpha1.ValidateDuration(*r.MinIdleTime, "minIdleTime"))
+	}
+	if r.Interval != nil {
+		errs = errs.Also(apisv1alpha1.ValidateDuration(*r.Interval, "interval"))
+	}
+	return errs
+}
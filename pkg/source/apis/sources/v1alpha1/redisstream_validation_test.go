@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+)
+
+func TestRedisStreamSource_Validate(t *testing.T) {
+	spec := func(mutate func(*RedisStreamSourceSpec)) RedisStreamSourceSpec {
+		s := RedisStreamSourceSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{Ref: &duckv1.KReference{
+					APIVersion: "v1",
+					Kind:       "Service",
+					Name:       "event-display",
+				}},
+			},
+			RedisConnection: apisv1alpha1.RedisConnection{Address: "redis://redis:6379"},
+			Stream:          "orders",
+		}
+		mutate(&s)
+		return s
+	}
+
+	tests := []struct {
+		name string
+		spec RedisStreamSourceSpec
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: spec(func(s *RedisStreamSourceSpec) {}),
+	}, {
+		name: "stream pattern",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = ""
+			s.StreamPattern = "orders:*"
+			s.DiscoveryInterval = ptr.String("PT10S")
+		}),
+	}, {
+		name: "list",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = ""
+			s.List = "jobs"
+		}),
+	}, {
+		name: "stream options with a list",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = ""
+			s.List = "jobs"
+			s.Group = "jobs"
+			s.GroupRetention = GroupRetentionRetain
+			s.StartFrom = ptr.String("0")
+			s.BatchSize = ptr.Int32(10)
+		}),
+		want: &apis.FieldError{
+			Message: "must not set the field(s)",
+			Paths:   []string{"spec.group", "spec.groupRetention", "spec.startFrom", "spec.batchSize"},
+			Details: "the stream options do not apply to lists",
+		},
+	}, {
+		name: "missing stream",
+		spec: spec(func(s *RedisStreamSourceSpec) { s.Stream = "" }),
+		want: apis.ErrMissingOneOf("spec.stream", "spec.streams", "spec.streamPattern", "spec.list"),
+	}, {
+		name: "stream and list",
+		spec: spec(func(s *RedisStreamSourceSpec) { s.List = "jobs" }),
+		want: apis.ErrGeneric("expected either streams or a list, got both",
+			"spec.stream", "spec.streams", "spec.streamPattern", "spec.list"),
+	}, {
+		name: "empty stream name",
+		spec: spec(func(s *RedisStreamSourceSpec) { s.Streams = []string{"tenant-a", ""} }),
+		want: apis.ErrInvalidArrayValue("", "spec.streams", 1),
+	}, {
+		name: "stream pattern in cluster mode",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.StreamPattern = "orders:*"
+			s.Cluster = &apisv1alpha1.RedisCluster{}
+		}),
+		want: apis.ErrGeneric("stream discovery is not supported in cluster mode", "spec.streamPattern", "spec.cluster"),
+	}, {
+		name: "streams sharing a slot in cluster mode",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = "{orders}:acme"
+			s.Streams = []string{"{orders}:globex"}
+			s.Cluster = &apisv1alpha1.RedisCluster{}
+		}),
+	}, {
+		name: "streams in different slots in cluster mode",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = "orders:acme"
+			s.Streams = []string{"orders:globex"}
+			s.Cluster = &apisv1alpha1.RedisCluster{}
+		}),
+		want: apis.ErrGeneric("the streams must share a hash slot in cluster mode, for instance with a {hash tag} in their names",
+			"spec.cluster", "spec.stream", "spec.streams"),
+	}, {
+		name: "missing sink",
+		spec: spec(func(s *RedisStreamSourceSpec) { s.Sink = duckv1.Destination{} }),
+		want: apis.ErrGeneric("expected at least one, got none", "spec.sink.ref", "spec.sink.uri"),
+	}, {
+		name: "invalid address",
+		spec: spec(func(s *RedisStreamSourceSpec) { s.Address = "redis-master:6379" }),
+		want: &apis.FieldError{
+			Message: "invalid address",
+			Paths:   []string{"spec.address"},
+			Details: "expected a redis://, rediss:// or unix:// URL",
+		},
+	}, {
+		name: "invalid read options",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Consumers = ptr.Int32(-1)
+			s.BatchSize = ptr.Int32(0)
+			s.BlockMilliseconds = ptr.Int32(0)
+			s.Encoding = "json"
+			s.GroupRetention = "Forever"
+			s.StartFrom = ptr.String("yesterday")
+		}),
+		want: apis.ErrInvalidValue("Forever", "spec.groupRetention").
+			Also(apis.ErrInvalidValue("yesterday", "spec.startFrom", "expected 0, $, an entry ID or an RFC 3339 timestamp")).
+			Also(apis.ErrInvalidValue(-1, "spec.consumers", "expected a positive number or zero")).
+			Also(apis.ErrInvalidValue(0, "spec.batchSize", "expected a positive number")).
+			Also(apis.ErrInvalidValue(0, "spec.blockMilliseconds", "expected a positive number")).
+			Also(apis.ErrInvalidValue("json", "spec.encoding")),
+	}, {
+		name: "invalid durations",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.DiscoveryInterval = ptr.String("30s")
+			s.Reclaim = &ReclaimSpec{MinIdleTime: ptr.String("PT5M"), Interval: ptr.String("PT0S")}
+		}),
+		want: apis.ErrInvalidValue("30s", "spec.discoveryInterval", "expected an ISO-8601 duration").
+			Also(apis.ErrInvalidValue("PT0S", "spec.reclaim.interval", "expected a positive duration")),
+	}, {
+		name: "autoscaling",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Group = "orders"
+			s.GroupRetention = GroupRetentionDeleteOnSourceDeletion
+			s.Autoscaling = &AutoscalingSpec{
+				MinReplicas:   ptr.Int32(0),
+				MaxReplicas:   10,
+				TargetPending: ptr.Int64(500),
+				Cooldown:      ptr.String("PT10M"),
+			}
+		}),
+	}, {
+		name: "autoscaling without group",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.GroupRetention = GroupRetentionDelete
+			s.Autoscaling = &AutoscalingSpec{MaxReplicas: 10}
+		}),
+		want: apis.ErrGeneric("autoscaling requires a group", "spec.autoscaling", "spec.group").
+			Also(apis.ErrGeneric("autoscaling requires the consumer group to be retained when consumers shut down",
+				"spec.autoscaling", "spec.groupRetention")),
+	}, {
+		name: "autoscaling a list",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Stream = ""
+			s.List = "jobs"
+			s.Autoscaling = &AutoscalingSpec{MaxReplicas: 10}
+		}),
+		want: apis.ErrGeneric("autoscaling is not supported for lists", "spec.autoscaling", "spec.list"),
+	}, {
+		name: "invalid autoscaling",
+		spec: spec(func(s *RedisStreamSourceSpec) {
+			s.Group = "orders"
+			s.GroupRetention = GroupRetentionRetain
+			s.Autoscaling = &AutoscalingSpec{
+				MinReplicas:   ptr.Int32(2),
+				TargetPending: ptr.Int64(0),
+				Cooldown:      ptr.String("5m"),
+			}
+		}),
+		want: apis.ErrInvalidValue(0, "spec.autoscaling.maxReplicas", "expected a positive number").
+			Also(apis.ErrOutOfBoundsValue(2, 0, 0, "spec.autoscaling.minReplicas")).
+			Also(apis.ErrInvalidValue(0, "spec.autoscaling.targetPending", "expected a positive number")).
+			Also(apis.ErrInvalidValue("5m", "spec.autoscaling.cooldown", "expected an ISO-8601 duration")),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &RedisStreamSource{
+				ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "ns"},
+				Spec:       tt.spec,
+			}
+			got := source.Validate(context.Background())
+			if diff := cmp.Diff(tt.want.Error(), got.Error()); diff != "" {
+				t.Error("Unexpected validation error (-want, +got):", diff)
+			}
+		})
+	}
+}
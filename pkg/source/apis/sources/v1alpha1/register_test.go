@@ -60,6 +60,8 @@ func TestKnownTypes(t *testing.T) {
 	for _, name := range []string{
 		"RedisStreamSource",
 		"RedisStreamSourceList",
+		"RedisPubSubSource",
+		"RedisPubSubSourceList",
 	} {
 		if _, ok := types[name]; !ok {
 			t.Errorf("Did not find %q as registered type", name)
@@ -22,68 +22,257 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	v1 "knative.dev/eventing/pkg/apis/duck/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedisConnection) DeepCopyInto(out *RedisConnection) {
+func (in *AutoscalingSpec) DeepCopyInto(out *AutoscalingSpec) {
 	*out = *in
-	if in.Options != nil {
-		in, out := &in.Options, &out.Options
-		*out = new(RedisConnectionOptions)
-		(*in).DeepCopyInto(*out)
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetPending != nil {
+		in, out := &in.TargetPending, &out.TargetPending
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingSpec.
+func (in *AutoscalingSpec) DeepCopy() *AutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyspaceSpec) DeepCopyInto(out *KeyspaceSpec) {
+	*out = *in
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyspaceSpec.
+func (in *KeyspaceSpec) DeepCopy() *KeyspaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyspaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReclaimSpec) DeepCopyInto(out *ReclaimSpec) {
+	*out = *in
+	if in.MinIdleTime != nil {
+		in, out := &in.MinIdleTime, &out.MinIdleTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(string)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConnection.
-func (in *RedisConnection) DeepCopy() *RedisConnection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReclaimSpec.
+func (in *ReclaimSpec) DeepCopy() *ReclaimSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RedisConnection)
+	out := new(ReclaimSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedisConnectionOptions) DeepCopyInto(out *RedisConnectionOptions) {
+func (in *RedisPubSubSource) DeepCopyInto(out *RedisPubSubSource) {
 	*out = *in
-	out.Password = in.Password
-	in.Cert.DeepCopyInto(&out.Cert)
-	in.Key.DeepCopyInto(&out.Key)
-	in.CACert.DeepCopyInto(&out.CACert)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisConnectionOptions.
-func (in *RedisConnectionOptions) DeepCopy() *RedisConnectionOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSource.
+func (in *RedisPubSubSource) DeepCopy() *RedisPubSubSource {
 	if in == nil {
 		return nil
 	}
-	out := new(RedisConnectionOptions)
+	out := new(RedisPubSubSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisPubSubSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSourceList) DeepCopyInto(out *RedisPubSubSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RedisPubSubSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceList.
+func (in *RedisPubSubSourceList) DeepCopy() *RedisPubSubSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RedisPubSubSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedisSecretValueFromSource) DeepCopyInto(out *RedisSecretValueFromSource) {
+func (in *RedisPubSubSourceSpec) DeepCopyInto(out *RedisPubSubSourceSpec) {
 	*out = *in
-	if in.SecretKeyRef != nil {
-		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(v1.SecretKeySelector)
+	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
+	in.RedisConnection.DeepCopyInto(&out.RedisConnection)
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keyspace != nil {
+		in, out := &in.Keyspace, &out.Keyspace
+		*out = new(KeyspaceSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSecretValueFromSource.
-func (in *RedisSecretValueFromSource) DeepCopy() *RedisSecretValueFromSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceSpec.
+func (in *RedisPubSubSourceSpec) DeepCopy() *RedisPubSubSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisPubSubSourceStatus) DeepCopyInto(out *RedisPubSubSourceStatus) {
+	*out = *in
+	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisPubSubSourceStatus.
+func (in *RedisPubSubSourceStatus) DeepCopy() *RedisPubSubSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisPubSubSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamConsumerStatus) DeepCopyInto(out *RedisStreamConsumerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamConsumerStatus.
+func (in *RedisStreamConsumerStatus) DeepCopy() *RedisStreamConsumerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamConsumerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamGroupStatus) DeepCopyInto(out *RedisStreamGroupStatus) {
+	*out = *in
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]RedisStreamConsumerStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamGroupStatus.
+func (in *RedisStreamGroupStatus) DeepCopy() *RedisStreamGroupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RedisSecretValueFromSource)
+	out := new(RedisStreamGroupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -154,11 +343,51 @@ func (in *RedisStreamSourceSpec) DeepCopyInto(out *RedisStreamSourceSpec) {
 	*out = *in
 	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
 	in.RedisConnection.DeepCopyInto(&out.RedisConnection)
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DiscoveryInterval != nil {
+		in, out := &in.DiscoveryInterval, &out.DiscoveryInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartFrom != nil {
+		in, out := &in.StartFrom, &out.StartFrom
+		*out = new(string)
+		**out = **in
+	}
 	if in.Consumers != nil {
 		in, out := &in.Consumers, &out.Consumers
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchSize != nil {
+		in, out := &in.BatchSize, &out.BatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BlockMilliseconds != nil {
+		in, out := &in.BlockMilliseconds, &out.BlockMilliseconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(v1.DeliverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reclaim != nil {
+		in, out := &in.Reclaim, &out.Reclaim
+		*out = new(ReclaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -176,6 +405,19 @@ func (in *RedisStreamSourceSpec) DeepCopy() *RedisStreamSourceSpec {
 func (in *RedisStreamSourceStatus) DeepCopyInto(out *RedisStreamSourceStatus) {
 	*out = *in
 	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	in.DeliveryStatus.DeepCopyInto(&out.DeliveryStatus)
+	if in.Streams != nil {
+		in, out := &in.Streams, &out.Streams
+		*out = make([]RedisStreamStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -188,3 +430,26 @@ func (in *RedisStreamSourceStatus) DeepCopy() *RedisStreamSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamStatus) DeepCopyInto(out *RedisStreamStatus) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]RedisStreamGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamStatus.
+func (in *RedisStreamStatus) DeepCopy() *RedisStreamStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	versioned "knative.dev/eventing-redis/pkg/source/client/clientset/versioned"
+	internalinterfaces "knative.dev/eventing-redis/pkg/source/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/client/listers/sources/v1alpha1"
+)
+
+// RedisPubSubSourceInformer provides access to a shared informer and lister for
+// RedisPubSubSources.
+type RedisPubSubSourceInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.RedisPubSubSourceLister
+}
+
+type redisPubSubSourceInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewRedisPubSubSourceInformer constructs a new informer for RedisPubSubSource type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewRedisPubSubSourceInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredRedisPubSubSourceInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredRedisPubSubSourceInformer constructs a new informer for RedisPubSubSource type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredRedisPubSubSourceInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SourcesV1alpha1().RedisPubSubSources(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SourcesV1alpha1().RedisPubSubSources(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&sourcesv1alpha1.RedisPubSubSource{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *redisPubSubSourceInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredRedisPubSubSourceInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *redisPubSubSourceInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&sourcesv1alpha1.RedisPubSubSource{}, f.defaultInformer)
+}
+
+func (f *redisPubSubSourceInformer) Lister() v1alpha1.RedisPubSubSourceLister {
+	return v1alpha1.NewRedisPubSubSourceLister(f.Informer().GetIndexer())
+}
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// RedisPubSubSourceListerExpansion allows custom methods to be added to
+// RedisPubSubSourceLister.
+type RedisPubSubSourceListerExpansion interface{}
+
+// RedisPubSubSourceNamespaceListerExpansion allows custom methods to be added to
+// RedisPubSubSourceNamespaceLister.
+type RedisPubSubSourceNamespaceListerExpansion interface{}
+
 // RedisStreamSourceListerExpansion allows custom methods to be added to
 // RedisStreamSourceLister.
 type RedisStreamSourceListerExpansion interface{}
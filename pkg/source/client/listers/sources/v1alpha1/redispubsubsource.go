@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// RedisPubSubSourceLister helps list RedisPubSubSources.
+// All objects returned here must be treated as read-only.
+type RedisPubSubSourceLister interface {
+	// List lists all RedisPubSubSources in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RedisPubSubSource, err error)
+	// RedisPubSubSources returns an object that can list and get RedisPubSubSources.
+	RedisPubSubSources(namespace string) RedisPubSubSourceNamespaceLister
+	RedisPubSubSourceListerExpansion
+}
+
+// redisPubSubSourceLister implements the RedisPubSubSourceLister interface.
+type redisPubSubSourceLister struct {
+	indexer cache.Indexer
+}
+
+// NewRedisPubSubSourceLister returns a new RedisPubSubSourceLister.
+func NewRedisPubSubSourceLister(indexer cache.Indexer) RedisPubSubSourceLister {
+	return &redisPubSubSourceLister{indexer: indexer}
+}
+
+// List lists all RedisPubSubSources in the indexer.
+func (s *redisPubSubSourceLister) List(selector labels.Selector) (ret []*v1alpha1.RedisPubSubSource, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RedisPubSubSource))
+	})
+	return ret, err
+}
+
+// RedisPubSubSources returns an object that can list and get RedisPubSubSources.
+func (s *redisPubSubSourceLister) RedisPubSubSources(namespace string) RedisPubSubSourceNamespaceLister {
+	return redisPubSubSourceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RedisPubSubSourceNamespaceLister helps list and get RedisPubSubSources.
+// All objects returned here must be treated as read-only.
+type RedisPubSubSourceNamespaceLister interface {
+	// List lists all RedisPubSubSources in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RedisPubSubSource, err error)
+	// Get retrieves the RedisPubSubSource from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RedisPubSubSource, error)
+	RedisPubSubSourceNamespaceListerExpansion
+}
+
+// redisPubSubSourceNamespaceLister implements the RedisPubSubSourceNamespaceLister
+// interface.
+type redisPubSubSourceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RedisPubSubSources in the indexer for a given namespace.
+func (s redisPubSubSourceNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RedisPubSubSource, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RedisPubSubSource))
+	})
+	return ret, err
+}
+
+// Get retrieves the RedisPubSubSource from the indexer for a given namespace and name.
+func (s redisPubSubSourceNamespaceLister) Get(name string) (*v1alpha1.RedisPubSubSource, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("redispubsubsource"), name)
+	}
+	return obj.(*v1alpha1.RedisPubSubSource), nil
+}
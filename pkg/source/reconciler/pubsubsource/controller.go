@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubsource
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
+
+	"knative.dev/eventing-redis/pkg/reconciler"
+	"knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	redispubsubsourceinformer "knative.dev/eventing-redis/pkg/source/client/injection/informers/sources/v1alpha1/redispubsubsource"
+	redispubsubsourcereconciler "knative.dev/eventing-redis/pkg/source/client/injection/reconciler/sources/v1alpha1/redispubsubsource"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource"
+)
+
+// envConfig will be used to extract the required environment variables using
+// github.com/kelseyhightower/envconfig. If this configuration cannot be extracted, then
+// NewController will panic.
+type envConfig struct {
+	Image string `envconfig:"PUBSUBSOURCE_RA_IMAGE" required:"true"`
+}
+
+// NewController initializes the controller and is called by the generated code
+// Registers event handlers to enqueue events
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	env := &envConfig{}
+	if err := envconfig.Process("", env); err != nil {
+		logging.FromContext(ctx).Panicf("unable to process RedisPubSubSource's required environment variables: %v", err)
+	}
+
+	deploymentInformer := deploymentinformer.Get(ctx)
+	redispubsubSourceInformer := redispubsubsourceinformer.Get(ctx)
+
+	r := &Reconciler{
+		dr:                  &reconciler.DeploymentReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		rbr:                 &reconciler.RoleBindingReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		sar:                 &reconciler.ServiceAccountReconciler{KubeClientSet: kubeclient.Get(ctx)},
+		receiveAdapterImage: env.Image,
+	}
+
+	impl := redispubsubsourcereconciler.NewImpl(ctx, r)
+
+	r.sinkResolver = resolver.NewURIResolverFromTracker(ctx, impl.Tracker)
+
+	// Get TLS secret and set TLS certificate, to pass data to receive adapter.
+	// Not rolling out new adapters on watch change.
+	if secret, err := kubeclient.Get(ctx).CoreV1().Secrets(system.Namespace()).Get(ctx, streamsource.TLSSecretName(), metav1.GetOptions{}); err == nil {
+		r.updateTLSSecret(ctx, secret)
+	} else if !apierrors.IsNotFound(err) {
+		logging.FromContext(ctx).With(zap.Error(err)).Info("Error reading TLS Secret'")
+	}
+
+	logging.FromContext(ctx).Info("Setting up event handlers")
+
+	redispubsubSourceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&v1alpha1.RedisPubSubSource{}),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsubsource
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	"knative.dev/eventing-redis/pkg/reconciler"
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	pubsubsourcereconciler "knative.dev/eventing-redis/pkg/source/client/injection/reconciler/sources/v1alpha1/redispubsubsource"
+	"knative.dev/eventing-redis/pkg/source/reconciler/pubsubsource/resources"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource"
+)
+
+const (
+	// The receive adapters of both sources need the same permissions.
+	adapterClusterRoleName = "knative-sources-redisstream-adapter"
+)
+
+func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
+}
+
+// Reconciler reconciles a pubsubsource object
+type Reconciler struct {
+	dr                  *reconciler.DeploymentReconciler
+	rbr                 *reconciler.RoleBindingReconciler
+	sar                 *reconciler.ServiceAccountReconciler
+	receiveAdapterImage string
+	sinkResolver        *resolver.URIResolver
+	tlsCert             string
+}
+
+// Check that our Reconciler implements ReconcileKind.
+var _ pubsubsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.RedisPubSubSource) pkgreconciler.Event {
+	dest := source.Spec.Sink.DeepCopy()
+	if dest.Ref != nil {
+		if dest.Ref.Namespace == "" {
+			dest.Ref.Namespace = source.GetNamespace()
+		}
+	}
+
+	sinkURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dest, source)
+	if err != nil {
+		source.Status.MarkNoSink("NotFound", "")
+		return newWarningSinkNotFound(dest)
+	}
+	source.Status.MarkSink(sinkURI.String())
+
+	expectedServiceAccount := eventingresources.MakeServiceAccount(source, resources.ServiceAccountName(source))
+	if sa, event := r.sar.ReconcileServiceAccount(ctx, source, expectedServiceAccount); sa == nil {
+		return event
+	}
+
+	expectedRoleBinding := resources.MakeRoleBinding(source, adapterClusterRoleName)
+	if rb, event := r.rbr.ReconcileRoleBinding(ctx, source, expectedRoleBinding); rb == nil {
+		return event
+	}
+
+	expectedDeployment := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinkURI.String(), r.tlsCert)
+	ra, event := r.dr.ReconcileDeployment(ctx, source, expectedDeployment)
+	if ra == nil {
+		return event
+	}
+	source.Status.PropagateDeploymentAvailability(ra)
+
+	return event
+}
+
+func (r *Reconciler) updateTLSSecret(ctx context.Context, secret *corev1.Secret) {
+	tlsSecret, err := streamsource.GetTLSSecret(secret.Data)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Error reading TLS configuration", zap.Error(err))
+		return
+	}
+	// For now just override the previous config.
+	r.tlsCert = tlsSecret.TLSCertificate
+}
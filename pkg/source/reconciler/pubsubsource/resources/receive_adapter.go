@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func AdapterName(source *sourcesv1alpha1.RedisPubSubSource) string {
+	return kmeta.ChildName(fmt.Sprintf("redispubsubsource-%s-", source.Name), string(source.UID))
+}
+
+// MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter Deployment for
+// RedisPubSub Sources. A single replica subscribes to the channels, since each
+// subscriber receives all the published messages.
+func MakeReceiveAdapter(source *sourcesv1alpha1.RedisPubSubSource, image string, sinkURI string, tlsCert string) *appsv1.Deployment {
+	labels := Labels(source.Name)
+	replicas := int32(1)
+	env := []corev1.EnvVar{{
+		Name:  "ADDRESS",
+		Value: source.Spec.Address,
+	}, {
+		Name:  "K_SINK",
+		Value: sinkURI,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}, {
+		Name: "NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}}
+	if len(source.Spec.Channels) > 0 {
+		env = append(env, corev1.EnvVar{Name: "CHANNELS", Value: strings.Join(source.Spec.Channels, ",")})
+	}
+	if len(source.Spec.Patterns) > 0 {
+		env = append(env, corev1.EnvVar{Name: "PATTERNS", Value: strings.Join(source.Spec.Patterns, ",")})
+	}
+	env = append(env, keyspaceEnv(source.Spec.Keyspace)...)
+	env = append(env, eventingresources.ConnectionEnv(source.Spec.RedisConnection)...)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: source.Namespace,
+			Name:      AdapterName(source),
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(source),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Replicas: &replicas,
+			// Subscribing twice would send the messages twice.
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: ServiceAccountName(source),
+					Containers: []corev1.Container{
+						{
+							Name:  "receive-adapter",
+							Image: image,
+							Env:   env,
+							Ports: []corev1.ContainerPort{{
+								Name:          "metrics",
+								ContainerPort: 9090,
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// keyspaceEnv returns the environment variables configuring the keyspace
+// notifications sent by the receive adapter.
+func keyspaceEnv(keyspace *sourcesv1alpha1.KeyspaceSpec) []corev1.EnvVar {
+	if keyspace == nil {
+		return nil
+	}
+	env := []corev1.EnvVar{{Name: "KEYSPACE", Value: "true"}}
+	if keyspace.Notifications != "" {
+		env = append(env, corev1.EnvVar{Name: "KEYSPACE_NOTIFICATIONS", Value: keyspace.Notifications})
+	}
+	if keyspace.KeyPrefix != "" {
+		env = append(env, corev1.EnvVar{Name: "KEYSPACE_KEY_PREFIX", Value: keyspace.KeyPrefix})
+	}
+	if len(keyspace.Operations) > 0 {
+		env = append(env, corev1.EnvVar{Name: "KEYSPACE_OPERATIONS", Value: strings.Join(keyspace.Operations, ",")})
+	}
+	if keyspace.Configure {
+		env = append(env, corev1.EnvVar{Name: "KEYSPACE_CONFIGURE", Value: "true"})
+	}
+	if keyspace.FetchValue {
+		env = append(env, corev1.EnvVar{Name: "KEYSPACE_FETCH_VALUE", Value: "true"})
+	}
+	return env
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestMakeReceiveAdapter(t *testing.T) {
+	src := &v1alpha1.RedisPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+			UID:       "1234",
+		},
+		Spec: v1alpha1.RedisPubSubSourceSpec{
+			RedisConnection: apisv1alpha1.RedisConnection{
+				Address: "redis.redis.svc.cluster.local:6379",
+			},
+			Channels: []string{"orders", "payments"},
+			Patterns: []string{"shipments.*"},
+			Keyspace: &v1alpha1.KeyspaceSpec{
+				Notifications: v1alpha1.KeyeventNotifications,
+				KeyPrefix:     "session:",
+				Operations:    []string{"set", "expired"},
+				FetchValue:    true,
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "cert")
+
+	if got.Name != AdapterName(src) || got.Namespace != "source-namespace" {
+		t.Errorf("unexpected deployment %s/%s", got.Namespace, got.Name)
+	}
+	if got.Spec.Replicas == nil || *got.Spec.Replicas != 1 {
+		t.Errorf("expected a single replica, got %v", got.Spec.Replicas)
+	}
+	if diff := cmp.Diff(Labels(src.Name), got.Spec.Template.Labels); diff != "" {
+		t.Errorf("unexpected labels (-want, +got) = %v", diff)
+	}
+
+	env := make(map[string]string)
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		if e.ValueFrom == nil {
+			env[e.Name] = e.Value
+		}
+	}
+	want := map[string]string{
+		"ADDRESS":         "redis.redis.svc.cluster.local:6379",
+		"K_SINK":          "sink-uri",
+		"TLS_CERTIFICATE": "cert",
+		"METRICS_DOMAIN":  "knative.dev/eventing",
+		"CHANNELS":        "orders,payments",
+		"PATTERNS":        "shipments.*",
+
+		"KEYSPACE":               "true",
+		"KEYSPACE_NOTIFICATIONS": "Keyevent",
+		"KEYSPACE_KEY_PREFIX":    "session:",
+		"KEYSPACE_OPERATIONS":    "set,expired",
+		"KEYSPACE_FETCH_VALUE":   "true",
+	}
+	if diff := cmp.Diff(want, env); diff != "" {
+		t.Errorf("unexpected env (-want, +got) = %v", diff)
+	}
+	if got.Spec.Template.Spec.ServiceAccountName != ServiceAccountName(src) {
+		t.Errorf("unexpected service account %s", got.Spec.Template.Spec.ServiceAccountName)
+	}
+	if len(got.OwnerReferences) != 1 || got.OwnerReferences[0].Kind != "RedisPubSubSource" {
+		t.Errorf("unexpected owner references %v", got.OwnerReferences)
+	}
+}
@@ -75,6 +75,7 @@ func (r *StatefulSetReconciler) ReconcileStatefulSet(ctx context.Context, owner
 		return nil, fmt.Errorf("statefulset %q is not owned by %s %q",
 			ra.Name, owner.GetGroupVersionKind().Kind, owner.GetObjectMeta().GetName())
 	} else if r.podSpecChanged(expected.Spec.Template.Spec, ra.Spec.Template.Spec) {
+		ra.Spec.Template.Spec = expected.Spec.Template.Spec
 		if ra, err = r.KubeClientSet.AppsV1().StatefulSets(namespace).Update(ctx, ra, metav1.UpdateOptions{}); err != nil {
 			return ra, err
 		}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+// autoscalingInterval is how often the backlog of an autoscaled source is
+// read from Redis, which bounds the time to wake up from zero consumers.
+const autoscalingInterval = 10 * time.Second
+
+// autoscale returns the number of consumers of the source. Without
+// autoscaling, it is the number of consumers in the spec. Otherwise it
+// follows the backlog of the consumer group last read from Redis, scaling up
+// right away and down once the cooldown elapsed since the last scaling.
+func autoscale(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, now time.Time) *int32 {
+	spec := source.Spec.Autoscaling
+	if spec == nil {
+		source.Status.Autoscaling = nil
+		return source.Spec.Consumers
+	}
+	minReplicas, maxReplicas := spec.GetMinReplicas(), spec.MaxReplicas
+
+	status := source.Status.Autoscaling
+	if status == nil {
+		// Start with a consumer, which creates the consumer group.
+		status = &sourcesv1alpha1.AutoscalingStatus{
+			Replicas:      clamp(1, minReplicas, maxReplicas),
+			LastScaleTime: &metav1.Time{Time: now},
+		}
+		source.Status.Autoscaling = status
+	}
+
+	desired := status.Replicas
+	streamReady := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionStreamReady)
+	switch {
+	case streamReady.IsTrue():
+		status.Backlog = backlog(source)
+		desired = desiredReplicas(spec, status.Backlog)
+	case streamReady.IsFalse():
+		// The consumers create the missing streams and consumer group.
+		if desired < 1 {
+			desired = 1
+		}
+	default:
+		// The backlog is unknown, keep the consumers.
+	}
+	desired = clamp(desired, minReplicas, maxReplicas)
+
+	inBounds := status.Replicas >= minReplicas && status.Replicas <= maxReplicas
+	coolingDown := status.LastScaleTime != nil && now.Sub(status.LastScaleTime.Time) < spec.GetCooldown()
+	if desired < status.Replicas && inBounds && coolingDown {
+		desired = status.Replicas
+	}
+
+	if desired != status.Replicas {
+		logging.FromContext(ctx).Infow("Scaling consumers",
+			"from", status.Replicas, "to", desired, "backlog", status.Backlog)
+		status.Replicas = desired
+		status.LastScaleTime = &metav1.Time{Time: now}
+	}
+	return ptr.Int32(status.Replicas)
+}
+
+// desiredReplicas returns the number of consumers needed for backlog, within
+// the bounds of the autoscaling.
+func desiredReplicas(spec *sourcesv1alpha1.AutoscalingSpec, backlog int64) int32 {
+	target := spec.GetTargetPending()
+	replicas := (backlog + target - 1) / target
+	if replicas > int64(spec.MaxReplicas) {
+		replicas = int64(spec.MaxReplicas)
+	}
+	return clamp(int32(replicas), spec.GetMinReplicas(), spec.MaxReplicas)
+}
+
+// backlog returns the number of entries pending or not delivered yet to the
+// consumer group of the source on all its streams. When the lag is unknown,
+// before Redis 7.0, undelivered entries count as one. So do streams without
+// the consumer group, which a consumer must create.
+func backlog(source *sourcesv1alpha1.RedisStreamSource) int64 {
+	var total int64
+	for _, stream := range source.Status.Streams {
+		found := false
+		for _, group := range stream.Groups {
+			if group.Name != source.Spec.Group {
+				continue
+			}
+			found = true
+			total += group.Pending
+			if group.Lag != nil {
+				total += *group.Lag
+			} else {
+				total++
+			}
+		}
+		if !found {
+			total++
+		}
+	}
+	return total
+}
+
+func clamp(n, lower, upper int32) int32 {
+	if n < lower {
+		return lower
+	}
+	if n > upper {
+		return upper
+	}
+	return n
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestAutoscale(t *testing.T) {
+	now := time.Now()
+	streams := func(pending int64, lag int64) []sourcesv1alpha1.RedisStreamStatus {
+		return []sourcesv1alpha1.RedisStreamStatus{{
+			Name:   "orders",
+			Length: 1000,
+			Groups: []sourcesv1alpha1.RedisStreamGroupStatus{{
+				Name:            "orders",
+				Pending:         pending,
+				Lag:             ptr.Int64(lag),
+				LastDeliveredID: "900-0",
+			}},
+		}}
+	}
+	scaled := func(replicas int32, ago time.Duration) *sourcesv1alpha1.AutoscalingStatus {
+		return &sourcesv1alpha1.AutoscalingStatus{
+			Replicas:      replicas,
+			LastScaleTime: &metav1.Time{Time: now.Add(-ago)},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		consumers   *int32
+		autoscaling *sourcesv1alpha1.AutoscalingSpec
+		status      *sourcesv1alpha1.AutoscalingStatus
+		streams     []sourcesv1alpha1.RedisStreamStatus
+		streamReady *bool
+		want        int32
+	}{{
+		name:      "no autoscaling",
+		consumers: ptr.Int32(3),
+		want:      3,
+	}, {
+		name:        "first consumer",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MinReplicas: ptr.Int32(0), MaxReplicas: 10},
+		streamReady: ptr.Bool(false),
+		want:        1,
+	}, {
+		name:        "scale up",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MaxReplicas: 10},
+		status:      scaled(1, time.Second),
+		streams:     streams(50, 200),
+		streamReady: ptr.Bool(true),
+		want:        3,
+	}, {
+		name:        "scale up to the maximum",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MaxReplicas: 10},
+		status:      scaled(1, time.Second),
+		streams:     streams(0, 100000),
+		streamReady: ptr.Bool(true),
+		want:        10,
+	}, {
+		name:        "scale down during cooldown",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MaxReplicas: 10},
+		status:      scaled(5, time.Minute),
+		streams:     streams(10, 0),
+		streamReady: ptr.Bool(true),
+		want:        5,
+	}, {
+		name:        "scale down after cooldown",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MaxReplicas: 10, TargetPending: ptr.Int64(5)},
+		status:      scaled(5, 10*time.Minute),
+		streams:     streams(10, 0),
+		streamReady: ptr.Bool(true),
+		want:        2,
+	}, {
+		name:        "scale to zero",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MinReplicas: ptr.Int32(0), MaxReplicas: 10, Cooldown: ptr.String("PT1M")},
+		status:      scaled(1, 2*time.Minute),
+		streams:     streams(0, 0),
+		streamReady: ptr.Bool(true),
+		want:        0,
+	}, {
+		name:        "wake up",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MinReplicas: ptr.Int32(0), MaxReplicas: 10},
+		status:      scaled(0, time.Second),
+		streams:     streams(0, 1),
+		streamReady: ptr.Bool(true),
+		want:        1,
+	}, {
+		name:        "wake up without lag",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MinReplicas: ptr.Int32(0), MaxReplicas: 10},
+		status:      scaled(0, time.Second),
+		streams: func() []sourcesv1alpha1.RedisStreamStatus {
+			s := streams(0, 0)
+			s[0].Groups[0].Lag = nil
+			return s
+		}(),
+		streamReady: ptr.Bool(true),
+		want:        1,
+	}, {
+		name:        "unknown backlog",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MinReplicas: ptr.Int32(0), MaxReplicas: 10},
+		status:      scaled(4, time.Hour),
+		want:        4,
+	}, {
+		name:        "maximum lowered",
+		autoscaling: &sourcesv1alpha1.AutoscalingSpec{MaxReplicas: 2},
+		status:      scaled(5, time.Second),
+		streams:     streams(1000, 0),
+		streamReady: ptr.Bool(true),
+		want:        2,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					Stream:      "orders",
+					Group:       "orders",
+					Consumers:   tt.consumers,
+					Autoscaling: tt.autoscaling,
+				},
+				Status: sourcesv1alpha1.RedisStreamSourceStatus{
+					Streams:     tt.streams,
+					Autoscaling: tt.status,
+				},
+			}
+			source.Status.InitializeConditions()
+			if tt.streamReady != nil && *tt.streamReady {
+				source.Status.MarkStreamReady()
+			} else if tt.streamReady != nil {
+				source.Status.MarkStreamNotReady("GroupNotFound", "")
+			}
+
+			got := autoscale(context.Background(), source, now)
+			if got == nil || *got != tt.want {
+				t.Errorf("Unexpected replicas, want %d, got %v", tt.want, got)
+			}
+			if tt.autoscaling != nil && source.Status.Autoscaling.Replicas != tt.want {
+				t.Errorf("Unexpected status replicas, want %d, got %d", tt.want, source.Status.Autoscaling.Replicas)
+			}
+		})
+	}
+}
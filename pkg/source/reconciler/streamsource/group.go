@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	"knative.dev/eventing-redis/pkg/source/reconciler/streamsource/resources"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+// startFrom returns the start position of the source, "$" by default.
+func startFrom(source *sourcesv1alpha1.RedisStreamSource) string {
+	if source.Spec.StartFrom == nil || *source.Spec.StartFrom == "" {
+		return "$"
+	}
+	return *source.Spec.StartFrom
+}
+
+// reconcileStartFrom moves the existing consumer groups of the source to its
+// start position when it changed since it was last applied. New groups are
+// created at that position by the receive adapter, so the position is only
+// recorded when none was applied yet. It does nothing for lists. Failures,
+// for instance when the controller cannot reach Redis, are reported in the
+// StartFromApplied condition and with a warning event, and retried on the
+// next reconcile without holding back the receive adapter.
+func (r *Reconciler) reconcileStartFrom(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) {
+	if source.Spec.List != "" {
+		// Lists are read from their consumption end.
+		return
+	}
+	desired := startFrom(source)
+	applied := source.Status.StartFrom
+	if applied == "" || desired == applied {
+		source.Status.MarkStartFromApplied(desired)
+		return
+	}
+
+	startID, err := scan.StartID(desired)
+	if err == nil {
+		err = r.setGroupsID(ctx, source, startID)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warnw("Cannot apply the start position", zap.String("startFrom", desired), zap.Error(err))
+		controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeWarning, "StartFromNotApplied", "Failed to apply start position %q: %v", desired, err)
+		source.Status.MarkStartFromNotApplied("StartFromNotApplied", "Failed to apply start position %q: %v", desired, err)
+		return
+	}
+	source.Status.MarkStartFromApplied(desired)
+}
+
+// setGroupsID sets the last delivered ID of the consumer groups of the source.
+func (r *Reconciler) setGroupsID(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, id string) error {
+	return r.forEachGroup(ctx, source, func(conn redis.Conn, stream string, name string) error {
+		logging.FromContext(ctx).Infow("Setting consumer group ID", zap.String("stream", stream), zap.String("group", name), zap.String("id", id))
+		_, err := conn.Do("XGROUP", "SETID", stream, name, id)
+		return err
+	})
+}
+
+// destroyGroups destroys the consumer groups of the source.
+func (r *Reconciler) destroyGroups(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) error {
+	return r.forEachGroup(ctx, source, func(conn redis.Conn, stream string, name string) error {
+		logging.FromContext(ctx).Infow("Destroying consumer group", zap.String("stream", stream), zap.String("group", name))
+		_, err := conn.Do("XGROUP", "DESTROY", stream, name)
+		return err
+	})
+}
+
+// forEachGroup calls f with each consumer group of the source existing in
+// Redis, along with its stream.
+func (r *Reconciler) forEachGroup(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource, f func(conn redis.Conn, stream string, name string) error) error {
+	pool, err := r.pool(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	conn := pool.Get()
+	defer conn.Close()
+
+	streams, err := sourceStreams(conn, source)
+	if err != nil {
+		return err
+	}
+
+	for _, stream := range streams {
+		groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", stream))
+		if err != nil {
+			if isNoSuchKey(err) {
+				// The stream and its groups are created by the receive adapter.
+				continue
+			}
+			return err
+		}
+
+		for name := range groups {
+			if !isSourceGroup(source, name) {
+				continue
+			}
+			if err := f(conn, stream, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pool returns the pool of connections to the Redis server of the source.
+func (r *Reconciler) pool(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) (*redis.Pool, error) {
+	config, err := redisconn.ConfigFor(ctx, r.kubeClientSet, source.Namespace, source.Spec.RedisConnection, r.tlsCert)
+	if err != nil {
+		return nil, err
+	}
+	return r.pools.get(types.NamespacedName{Namespace: source.Namespace, Name: source.Name}, config)
+}
+
+// sourceStreams returns the streams of the source, followed by those matching
+// its stream pattern.
+func sourceStreams(conn redis.Conn, source *sourcesv1alpha1.RedisStreamSource) ([]string, error) {
+	streams := source.Spec.StreamNames()
+	if source.Spec.StreamPattern != "" {
+		matched, err := scan.MatchStreams(conn, source.Spec.StreamPattern)
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, matched...)
+	}
+
+	seen := make(map[string]bool, len(streams))
+	unique := streams[:0]
+	for _, stream := range streams {
+		if !seen[stream] {
+			seen[stream] = true
+			unique = append(unique, stream)
+		}
+	}
+	return unique, nil
+}
+
+// isSourceGroup returns true if the consumer group name belongs to the source.
+// Without a group in the spec, each receive adapter pod has its own group
+// named after the pod.
+func isSourceGroup(source *sourcesv1alpha1.RedisStreamSource, name string) bool {
+	if source.Spec.Group != "" {
+		return name == source.Spec.Group
+	}
+	return strings.HasPrefix(name, resources.AdapterName(source)+"-")
+}
+
+// isNoSuchKey returns true if err reports that the stream does not exist.
+func isNoSuchKey(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no such key") || strings.Contains(msg, "no longer exists")
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/ptr"
+
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+)
+
+func TestReconcileStartFrom(t *testing.T) {
+	tests := []struct {
+		name      string
+		startFrom *string
+		applied   string
+		// wantSetID tells whether the groups are moved, which fails as
+		// Redis is unreachable.
+		wantSetID bool
+		want      string
+	}{{
+		name: "default, nothing applied",
+		want: "$",
+	}, {
+		name:      "nothing applied",
+		startFrom: ptr.String("0"),
+		want:      "0",
+	}, {
+		name:      "already applied",
+		startFrom: ptr.String("0"),
+		applied:   "0",
+		want:      "0",
+	}, {
+		name:      "changed",
+		startFrom: ptr.String("0"),
+		applied:   "$",
+		wantSetID: true,
+		want:      "$",
+	}, {
+		name:      "changed to the default",
+		applied:   "0",
+		wantSetID: true,
+		want:      "0",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &sourcesv1alpha1.RedisStreamSource{
+				Spec: sourcesv1alpha1.RedisStreamSourceSpec{
+					RedisConnection: apisv1alpha1.RedisConnection{Address: "redis://127.0.0.1:1"},
+					Stream:          "mystream",
+					StartFrom:       tt.startFrom,
+				},
+			}
+			source.Status.StartFrom = tt.applied
+
+			r := &Reconciler{kubeClientSet: fake.NewSimpleClientset()}
+			defer r.pools.remove(types.NamespacedName{Namespace: source.Namespace, Name: source.Name})
+			ctx := controller.WithEventRecorder(logtesting.TestContextWithLogger(t), record.NewFakeRecorder(10))
+			r.reconcileStartFrom(ctx, source)
+
+			cond := source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionStartFromApplied)
+			if cond.IsTrue() == tt.wantSetID {
+				t.Errorf("got StartFromApplied %v, want SETID %v", cond.Status, tt.wantSetID)
+			}
+			if got := source.Status.StartFrom; got != tt.want {
+				t.Errorf("got applied start position %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
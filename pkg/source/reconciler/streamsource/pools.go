@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gomodule/redigo/redis"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+// pools caches a pool of connections to Redis per source, so that the state
+// of the streams is read without dialing Redis on every reconciliation. A
+// pool is replaced when the connection of its source changes. The zero value
+// is ready to use.
+type pools struct {
+	mu      sync.Mutex
+	entries map[types.NamespacedName]poolEntry
+}
+
+type poolEntry struct {
+	config redisconn.Config
+	pool   *redis.Pool
+}
+
+// get returns the pool of the source named key, connecting with config.
+func (p *pools) get(key types.NamespacedName, config redisconn.Config) (*redis.Pool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.entries[key]; ok {
+		if reflect.DeepEqual(entry.config, config) {
+			return entry.pool, nil
+		}
+		entry.pool.Close()
+		delete(p.entries, key)
+	}
+
+	pool, err := redisconn.NewPool(config)
+	if err != nil {
+		return nil, err
+	}
+	if p.entries == nil {
+		p.entries = make(map[types.NamespacedName]poolEntry)
+	}
+	p.entries[key] = poolEntry{config: config, pool: pool}
+	return pool, nil
+}
+
+// remove closes the pool of the source named key, if any.
+func (p *pools) remove(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry, ok := p.entries[key]; ok {
+		entry.pool.Close()
+		delete(p.entries, key)
+	}
+}
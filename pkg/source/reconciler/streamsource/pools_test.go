@@ -0,0 +1,53 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing-redis/pkg/redisconn"
+)
+
+func TestPools(t *testing.T) {
+	var p pools
+	key := types.NamespacedName{Namespace: "ns", Name: "source"}
+	config := redisconn.Config{Address: "redis://redis:6379"}
+
+	first, err := p.get(key, config)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if got, _ := p.get(key, config); got != first {
+		t.Error("Expected the pool to be reused")
+	}
+
+	config.Password = "secret"
+	second, err := p.get(key, config)
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if second == first {
+		t.Error("Expected a new pool when the connection changed")
+	}
+
+	p.remove(key)
+	if got, _ := p.get(key, config); got == second {
+		t.Error("Expected a new pool once removed")
+	}
+}
@@ -18,24 +18,101 @@ package resources
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
 
+	eventingresources "knative.dev/eventing-redis/pkg/reconciler/resources"
 	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 )
 
+// metricsPort is the port the receive adapter exports its metrics on.
+const metricsPort = 9090
+
 func AdapterName(source *sourcesv1alpha1.RedisStreamSource) string {
 	return kmeta.ChildName(fmt.Sprintf("redissource-%s-", source.Name), "1234") //TODO: must be no more than 63 characters, spec.hostname: Invalid value error
 }
 
 // MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter Deployment for
 // RedisStream Sources.
-func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string, sinkURI string, numConsumers string, tlsCert string) *appsv1.StatefulSet {
+func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string, sinkURI string, deadLetterSinkURI string, numConsumers string, tlsCert string) *appsv1.StatefulSet {
 	labels := Labels(source.Name)
+	env := []corev1.EnvVar{{
+		Name:  "STREAM",
+		Value: source.Spec.Stream,
+	}, {
+		Name:  "GROUP",
+		Value: source.Spec.Group,
+	}, {
+		Name:  "ADDRESS",
+		Value: source.Spec.Address,
+	}, {
+		Name:  "K_SINK",
+		Value: sinkURI,
+	}, {
+		Name:  "NUM_CONSUMERS",
+		Value: numConsumers,
+	}, {
+		Name:  "TLS_CERTIFICATE",
+		Value: tlsCert,
+	}, {
+		Name: "NAMESPACE",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.namespace",
+			},
+		},
+	}, {
+		Name: "NAME",
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	}, {
+		Name:  "SOURCE_NAME",
+		Value: source.Name,
+	}, {
+		Name:  "METRICS_DOMAIN",
+		Value: "knative.dev/eventing",
+	}, eventingresources.ObservabilityEnv(metricsPort)}
+	if len(source.Spec.Streams) > 0 {
+		env = append(env, corev1.EnvVar{Name: "STREAMS", Value: strings.Join(source.Spec.Streams, ",")})
+	}
+	if source.Spec.StreamPattern != "" {
+		env = append(env, corev1.EnvVar{Name: "STREAM_PATTERN", Value: source.Spec.StreamPattern})
+	}
+	if source.Spec.DiscoveryInterval != nil && *source.Spec.DiscoveryInterval != "" {
+		env = append(env, corev1.EnvVar{Name: "DISCOVERY_INTERVAL", Value: *source.Spec.DiscoveryInterval})
+	}
+	if source.Spec.List != "" {
+		env = append(env, corev1.EnvVar{Name: "LIST", Value: source.Spec.List})
+	}
+	if source.Spec.BatchSize != nil {
+		env = append(env, corev1.EnvVar{Name: "BATCH_SIZE", Value: strconv.Itoa(int(*source.Spec.BatchSize))})
+	}
+	if source.Spec.BlockMilliseconds != nil {
+		env = append(env, corev1.EnvVar{Name: "BLOCK_MILLISECONDS", Value: strconv.Itoa(int(*source.Spec.BlockMilliseconds))})
+	}
+	if source.Spec.GroupRetention != "" {
+		env = append(env, corev1.EnvVar{Name: "GROUP_RETENTION", Value: source.Spec.GroupRetention})
+	}
+	if source.Spec.StartFrom != nil && *source.Spec.StartFrom != "" {
+		env = append(env, corev1.EnvVar{Name: "START_FROM", Value: *source.Spec.StartFrom})
+	}
+	if source.Spec.Encoding != "" {
+		env = append(env, corev1.EnvVar{Name: "ENCODING", Value: source.Spec.Encoding})
+	}
+	env = append(env, eventingresources.ConnectionEnv(source.Spec.RedisConnection)...)
+	env = append(env, deliveryEnv(source.Spec.Delivery, deadLetterSinkURI)...)
+	env = append(env, reclaimEnv(source.Spec.Reclaim)...)
+
 	return &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: source.Namespace,
@@ -60,45 +137,10 @@ func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string,
 						{
 							Name:  "receive-adapter",
 							Image: image,
-							Env: []corev1.EnvVar{{
-								Name:  "STREAM",
-								Value: source.Spec.Stream,
-							}, {
-								Name:  "GROUP",
-								Value: source.Spec.Group,
-							}, {
-								Name:  "ADDRESS",
-								Value: source.Spec.Address,
-							}, {
-								Name:  "K_SINK",
-								Value: sinkURI,
-							}, {
-								Name:  "NUM_CONSUMERS",
-								Value: numConsumers,
-							}, {
-								Name:  "TLS_CERTIFICATE",
-								Value: tlsCert,
-							}, {
-								Name: "NAMESPACE",
-								ValueFrom: &corev1.EnvVarSource{
-									FieldRef: &corev1.ObjectFieldSelector{
-										FieldPath: "metadata.namespace",
-									},
-								},
-							}, {
-								Name: "NAME",
-								ValueFrom: &corev1.EnvVarSource{
-									FieldRef: &corev1.ObjectFieldSelector{
-										FieldPath: "metadata.name",
-									},
-								},
-							}, {
-								Name:  "METRICS_DOMAIN",
-								Value: "knative.dev/eventing",
-							}},
+							Env:   env,
 							Ports: []corev1.ContainerPort{{
 								Name:          "metrics",
-								ContainerPort: 9090,
+								ContainerPort: metricsPort,
 							}},
 						},
 					},
@@ -107,3 +149,41 @@ func MakeReceiveAdapter(source *sourcesv1alpha1.RedisStreamSource, image string,
 		},
 	}
 }
+
+// deliveryEnv returns the environment variables configuring how the receive
+// adapter retries failed deliveries and where it sends undeliverable events.
+func deliveryEnv(delivery *eventingduckv1.DeliverySpec, deadLetterSinkURI string) []corev1.EnvVar {
+	if delivery == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if delivery.Retry != nil {
+		env = append(env, corev1.EnvVar{Name: "RETRY", Value: strconv.Itoa(int(*delivery.Retry))})
+	}
+	if delivery.BackoffPolicy != nil {
+		env = append(env, corev1.EnvVar{Name: "BACKOFF_POLICY", Value: string(*delivery.BackoffPolicy)})
+	}
+	if delivery.BackoffDelay != nil {
+		env = append(env, corev1.EnvVar{Name: "BACKOFF_DELAY", Value: *delivery.BackoffDelay})
+	}
+	if deadLetterSinkURI != "" {
+		env = append(env, corev1.EnvVar{Name: "DEAD_LETTER_SINK", Value: deadLetterSinkURI})
+	}
+	return env
+}
+
+// reclaimEnv returns the environment variables configuring how the receive
+// adapter reclaims stale pending entries.
+func reclaimEnv(reclaim *sourcesv1alpha1.ReclaimSpec) []corev1.EnvVar {
+	if reclaim == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if reclaim.MinIdleTime != nil {
+		env = append(env, corev1.EnvVar{Name: "RECLAIM_MIN_IDLE_TIME", Value: *reclaim.MinIdleTime})
+	}
+	if reclaim.Interval != nil {
+		env = append(env, corev1.EnvVar{Name: "RECLAIM_INTERVAL", Value: *reclaim.Interval})
+	}
+	return env
+}
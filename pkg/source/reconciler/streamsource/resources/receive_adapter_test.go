@@ -24,7 +24,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/kmp"
+	"knative.dev/pkg/ptr"
 
+	apisv1alpha1 "knative.dev/eventing-redis/pkg/apis/v1alpha1"
 	v1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
 )
 
@@ -35,14 +37,14 @@ func TestMakeReceiveAdapter(t *testing.T) {
 			Namespace: "source-namespace",
 		},
 		Spec: v1alpha1.RedisStreamSourceSpec{
-			RedisConnection: v1alpha1.RedisConnection{
+			RedisConnection: apisv1alpha1.RedisConnection{
 				Address: "redis.redis.svc.cluster.local:6379",
 			},
 			Stream: "mystream",
 		},
 	}
 
-	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "5", "")
+	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "", "5", "")
 
 	one := int32(1)
 	labels := Labels(src.Name)
@@ -100,9 +102,15 @@ func TestMakeReceiveAdapter(t *testing.T) {
 											FieldPath: "metadata.name",
 										},
 									},
+								}, {
+									Name:  "SOURCE_NAME",
+									Value: src.Name,
 								}, {
 									Name:  "METRICS_DOMAIN",
 									Value: "knative.dev/eventing",
+								}, {
+									Name:  "K_OBSERVABILITY_CONFIG",
+									Value: `{"metrics":{"protocol":"prometheus","endpoint":":9090"}}`,
 								},
 							},
 							Ports: []corev1.ContainerPort{{
@@ -120,3 +128,33 @@ func TestMakeReceiveAdapter(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakeReceiveAdapterStreams(t *testing.T) {
+	src := &v1alpha1.RedisStreamSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source-name",
+			Namespace: "source-namespace",
+		},
+		Spec: v1alpha1.RedisStreamSourceSpec{
+			Streams:           []string{"tenant-a", "tenant-b"},
+			StreamPattern:     "orders:*",
+			DiscoveryInterval: ptr.String("PT10S"),
+		},
+	}
+
+	got := MakeReceiveAdapter(src, "test-image", "sink-uri", "", "1", "")
+
+	env := map[string]string{}
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if env["STREAMS"] != "tenant-a,tenant-b" {
+		t.Errorf("unexpected STREAMS %q", env["STREAMS"])
+	}
+	if env["STREAM_PATTERN"] != "orders:*" {
+		t.Errorf("unexpected STREAM_PATTERN %q", env["STREAM_PATTERN"])
+	}
+	if env["DISCOVERY_INTERVAL"] != "PT10S" {
+		t.Errorf("unexpected DISCOVERY_INTERVAL %q", env["DISCOVERY_INTERVAL"])
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"go.uber.org/zap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	sourcesv1alpha1 "knative.dev/eventing-redis/pkg/source/apis/sources/v1alpha1"
+	scan "knative.dev/eventing-redis/pkg/source/redis"
+)
+
+const (
+	// streamStatusInterval is how often the state of the streams of a ready
+	// source is read from Redis.
+	streamStatusInterval = 30 * time.Second
+
+	// streamStatusRetryInterval is how often it is read while a stream or a
+	// consumer group is missing, for instance until the receive adapter
+	// created them.
+	streamStatusRetryInterval = 5 * time.Second
+)
+
+// reconcileStreamStatus reads the state of the streams of the source and of
+// their consumer groups from Redis, and requeues the source to read it again
+// later. Lists have no stream status.
+func (r *Reconciler) reconcileStreamStatus(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
+	if source.Spec.List != "" {
+		source.Status.Streams = nil
+		source.Status.MarkStreamReady()
+		return nil
+	}
+
+	if err := r.readStreamStatus(ctx, source); err != nil {
+		logging.FromContext(ctx).Warnw("Cannot read the streams", zap.Error(err))
+		source.Status.MarkStreamUnknown("RedisUnavailable", "Cannot read the streams: %v", err)
+		return controller.NewRequeueAfter(streamStatusRetryInterval)
+	}
+	if !source.Status.GetCondition(sourcesv1alpha1.RedisStreamConditionStreamReady).IsTrue() {
+		return controller.NewRequeueAfter(streamStatusRetryInterval)
+	}
+	if source.Spec.Autoscaling != nil {
+		return controller.NewRequeueAfter(autoscalingInterval)
+	}
+	return controller.NewRequeueAfter(streamStatusInterval)
+}
+
+// readStreamStatus sets the stream status and the StreamReady condition of
+// the source. The streams named in the spec must exist and have a consumer
+// group of the source, the streams matching the pattern are only reported.
+func (r *Reconciler) readStreamStatus(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) error {
+	pool, err := r.pool(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	conn := pool.Get()
+	defer conn.Close()
+
+	streams, err := sourceStreams(conn, source)
+	if err != nil {
+		return err
+	}
+
+	required := make(map[string]bool)
+	for _, stream := range source.Spec.StreamNames() {
+		required[stream] = true
+	}
+
+	var reason, message string
+	statuses := make([]sourcesv1alpha1.RedisStreamStatus, 0, len(streams))
+	for _, stream := range streams {
+		status, err := streamStatus(conn, source, stream)
+		if err != nil {
+			if !isNoSuchKey(err) {
+				return err
+			}
+			if required[stream] && reason == "" {
+				reason, message = "StreamNotFound", fmt.Sprintf("The stream %q does not exist.", stream)
+			}
+			continue
+		}
+		if required[stream] && len(status.Groups) == 0 && reason == "" {
+			reason, message = "GroupNotFound", fmt.Sprintf("The stream %q has no consumer group of the source.", stream)
+		}
+		statuses = append(statuses, status)
+	}
+
+	source.Status.Streams = statuses
+	if reason != "" {
+		source.Status.MarkStreamNotReady(reason, "%s", message)
+	} else {
+		source.Status.MarkStreamReady()
+	}
+	return nil
+}
+
+// streamStatus returns the state of the stream and of the consumer groups of
+// the source on it.
+func streamStatus(conn redis.Conn, source *sourcesv1alpha1.RedisStreamSource, stream string) (sourcesv1alpha1.RedisStreamStatus, error) {
+	info, err := scan.ScanXInfoStreamReply(conn.Do("XINFO", "STREAM", stream))
+	if err != nil {
+		return sourcesv1alpha1.RedisStreamStatus{}, err
+	}
+	status := sourcesv1alpha1.RedisStreamStatus{
+		Name:   stream,
+		Length: int64(info.Length),
+	}
+
+	groups, err := scan.ScanXInfoGroupReply(conn.Do("XINFO", "GROUPS", stream))
+	if err != nil {
+		return sourcesv1alpha1.RedisStreamStatus{}, err
+	}
+	for name, group := range groups {
+		if !isSourceGroup(source, name) {
+			continue
+		}
+		groupStatus := sourcesv1alpha1.RedisStreamGroupStatus{
+			Name:            name,
+			Pending:         int64(group.Pending),
+			LastDeliveredID: group.LastDeliveredId,
+		}
+		switch {
+		case group.Lag != nil:
+			lag := int64(*group.Lag)
+			groupStatus.Lag = &lag
+		case group.LastDeliveredId == info.LastGeneratedID:
+			groupStatus.Lag = ptr.Int64(0)
+		}
+
+		consumers, err := scan.ScanXInfoConsumersReply(conn.Do("XINFO", "CONSUMERS", stream, name))
+		if err != nil {
+			return sourcesv1alpha1.RedisStreamStatus{}, err
+		}
+		for _, consumer := range consumers {
+			groupStatus.Consumers = append(groupStatus.Consumers, sourcesv1alpha1.RedisStreamConsumerStatus{
+				Name:             consumer.Name,
+				Pending:          int64(consumer.Pending),
+				IdleMilliseconds: roundIdle(consumer.Idle),
+			})
+		}
+		sort.Slice(groupStatus.Consumers, func(i, j int) bool {
+			return groupStatus.Consumers[i].Name < groupStatus.Consumers[j].Name
+		})
+		status.Groups = append(status.Groups, groupStatus)
+	}
+	sort.Slice(status.Groups, func(i, j int) bool {
+		return status.Groups[i].Name < status.Groups[j].Name
+	})
+	return status, nil
+}
+
+// roundIdle rounds the idle time of a consumer, in milliseconds, down to the
+// interval at which the stream status is read. The idle time of an active
+// consumer differs on every read, which would update the status every time
+// otherwise.
+func roundIdle(idle int) int64 {
+	interval := streamStatusInterval.Milliseconds()
+	return int64(idle) / interval * interval
+}
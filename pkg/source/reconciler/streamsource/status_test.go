@@ -0,0 +1,37 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package streamsource
+
+import "testing"
+
+func TestRoundIdle(t *testing.T) {
+	tests := []struct {
+		idle int
+		want int64
+	}{
+		{idle: 0, want: 0},
+		{idle: 2500, want: 0},
+		{idle: 29999, want: 0},
+		{idle: 30000, want: 30000},
+		{idle: 95000, want: 90000},
+	}
+	for _, tt := range tests {
+		if got := roundIdle(tt.idle); got != tt.want {
+			t.Errorf("roundIdle(%d) = %d, want %d", tt.idle, got, tt.want)
+		}
+	}
+}
@@ -19,12 +19,17 @@ package streamsource
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
@@ -41,17 +46,34 @@ import (
 const (
 	component              = "redisstreamsource"
 	adapterClusterRoleName = "knative-sources-redisstream-adapter"
+
+	// adapterShutdownPollInterval is how often a deleted source checks that
+	// its receive adapter is gone before destroying the consumer groups.
+	adapterShutdownPollInterval = 5 * time.Second
+
+	// finalizeTimeout is how long a deleted source tries to destroy its
+	// consumer groups before giving up, for instance when Redis is gone.
+	finalizeTimeout = 10 * time.Minute
 )
 
 func newFinalizedNormal(namespace, name string) pkgreconciler.Event {
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "RedisStreamSourceFinalized", "RedisStreamSource finalized: \"%s/%s\"", namespace, name)
 }
 
+func newWarningGroupsNotDestroyed(err error) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "GroupsNotDestroyed", "Failed to destroy consumer groups: %v", err)
+}
+
 func newWarningSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
 	b, _ := json.Marshal(sink)
 	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "SinkNotFound", "Sink not found: %s", string(b))
 }
 
+func newWarningDeadLetterSinkNotFound(sink *duckv1.Destination) pkgreconciler.Event {
+	b, _ := json.Marshal(sink)
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "DeadLetterSinkNotFound", "Dead letter sink not found: %s", string(b))
+}
+
 // Reconciler reconciles a streamsource object
 type Reconciler struct {
 	kubeClientSet       kubernetes.Interface
@@ -64,6 +86,9 @@ type Reconciler struct {
 	configs             reconcilersource.ConfigAccessor
 	numConsumers        string
 	tlsCert             string
+
+	// pools are the connections to Redis of the sources.
+	pools pools
 }
 
 // Check that our Reconciler implements ReconcileKind.
@@ -89,6 +114,23 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 	}
 	source.Status.MarkSink(sinkURI.String())
 
+	deadLetterSinkURI := ""
+	if source.Spec.Delivery != nil && source.Spec.Delivery.DeadLetterSink != nil {
+		dls := source.Spec.Delivery.DeadLetterSink.DeepCopy()
+		if dls.Ref != nil && dls.Ref.Namespace == "" {
+			dls.Ref.Namespace = source.GetNamespace()
+		}
+		dlsURI, err := r.sinkResolver.URIFromDestinationV1(ctx, *dls, source)
+		if err != nil {
+			source.Status.MarkNoDeadLetterSink("DeadLetterSinkNotFound", "")
+			return newWarningDeadLetterSinkNotFound(dls)
+		}
+		source.Status.MarkDeadLetterSink(dlsURI)
+		deadLetterSinkURI = dlsURI.String()
+	} else {
+		source.Status.MarkDeadLetterSink(nil)
+	}
+
 	expectedServiceAccount := eventingresources.MakeServiceAccount(source, resources.ServiceAccountName(source))
 	sa, event := r.sar.ReconcileServiceAccount(ctx, source, expectedServiceAccount)
 	if sa == nil {
@@ -103,7 +145,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 		return event
 	}
 
-	expectedStatefulSet := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinkURI.String(), r.numConsumers, r.tlsCert)
+	// Move the existing consumer groups before rolling out the receive
+	// adapter, which creates the missing ones at the start position.
+	r.reconcileStartFrom(ctx, source)
+
+	// Read the streams before rolling out the receive adapter, whose
+	// replicas follow their backlog when autoscaled.
+	streamEvent := r.reconcileStreamStatus(ctx, source)
+
+	expectedStatefulSet := resources.MakeReceiveAdapter(source, r.receiveAdapterImage, sinkURI.String(), deadLetterSinkURI, r.numConsumers, r.tlsCert)
+	expectedStatefulSet.Spec.Replicas = autoscale(ctx, source, time.Now())
 	ra, event := r.ssr.ReconcileStatefulSet(ctx, source, expectedStatefulSet)
 	if ra == nil {
 		if source.Status.Annotations == nil {
@@ -114,12 +165,51 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, source *sourcesv1alpha1.
 	}
 	source.Status.PropagateStatefulSetAvailability(ra)
 
-	return nil
+	return streamEvent
 }
 
 func (r *Reconciler) FinalizeKind(ctx context.Context, source *sourcesv1alpha1.RedisStreamSource) pkgreconciler.Event {
-	//Nothing to do since adapter will gracefully shutdown the consumers
-	return nil //ok to remove finalizer
+	defer r.pools.remove(types.NamespacedName{Namespace: source.Namespace, Name: source.Name})
+
+	if source.Spec.List != "" {
+		// Lists have no consumer groups, the consumers requeue their items on shutdown.
+		return newFinalizedNormal(source.Namespace, source.Name)
+	}
+	if source.Spec.GetGroupRetention() != sourcesv1alpha1.GroupRetentionDeleteOnSourceDeletion {
+		// Either the adapter destroys the consumer groups on shutdown or they are retained.
+		return newFinalizedNormal(source.Namespace, source.Name)
+	}
+
+	// The source is not held back forever, for instance when Redis or the
+	// namespace is gone. The consumer groups are then left in Redis.
+	expired := time.Since(source.DeletionTimestamp.Time) > finalizeTimeout
+
+	// Wait for the consumers to gracefully shutdown, so that they do not
+	// recreate the consumer groups.
+	statefulSets := r.kubeClientSet.AppsV1().StatefulSets(source.Namespace)
+	ra, err := statefulSets.Get(ctx, resources.AdapterName(source), metav1.GetOptions{})
+	if err == nil && !expired {
+		if ra.DeletionTimestamp == nil {
+			propagation := metav1.DeletePropagationForeground
+			if err := statefulSets.Delete(ctx, ra.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		return controller.NewRequeueAfter(adapterShutdownPollInterval)
+	} else if err != nil && !apierrors.IsNotFound(err) && !expired {
+		return err
+	}
+
+	if err := r.destroyGroups(ctx, source); err != nil {
+		// A missing connection secret cannot be waited for.
+		if expired || apierrors.IsNotFound(err) {
+			logging.FromContext(ctx).Warnw("Giving up destroying the consumer groups", zap.Error(err))
+			controller.GetEventRecorder(ctx).Eventf(source, corev1.EventTypeWarning, "GroupsLeft", "Gave up destroying consumer groups, they are left in Redis: %v", err)
+			return newFinalizedNormal(source.Namespace, source.Name)
+		}
+		return newWarningGroupsNotDestroyed(err)
+	}
+	return newFinalizedNormal(source.Namespace, source.Name) //ok to remove finalizer
 }
 
 func (r *Reconciler) updateRedisConfig(ctx context.Context, configMap *corev1.ConfigMap) {
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"sort"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// scanCount is the number of keys SCAN is hinted to check per call.
+const scanCount = 100
+
+// MatchStreams returns the names of the streams matching the glob-style
+// pattern, sorted. It requires Redis 6.0 or later, for the TYPE option of
+// SCAN.
+func MatchStreams(conn redis.Conn, pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	cursor := "0"
+	for {
+		next, keys, err := ScanScanReply(conn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", scanCount, "TYPE", "stream"))
+		if err != nil {
+			return nil, err
+		}
+		// SCAN may return a key more than once.
+		for _, key := range keys {
+			seen[key] = true
+		}
+		if next == "0" {
+			break
+		}
+		cursor = next
+	}
+
+	streams := make([]string, 0, len(seen))
+	for stream := range seen {
+		streams = append(streams, stream)
+	}
+	sort.Strings(streams)
+	return streams, nil
+}
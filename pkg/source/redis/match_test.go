@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMatchStreams(t *testing.T) {
+	conn := &scanConn{replies: []interface{}{
+		[]interface{}{[]byte("17"), []interface{}{[]byte("orders:{globex}"), []byte("orders:{acme}")}},
+		[]interface{}{[]byte("0"), []interface{}{[]byte("orders:{acme}"), []byte("orders:{initech}")}},
+	}}
+
+	streams, err := MatchStreams(conn, "orders:*")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"orders:{acme}", "orders:{globex}", "orders:{initech}"}, streams); diff != "" {
+		t.Errorf("Unexpected streams (-want, +got): %v", diff)
+	}
+	if diff := cmp.Diff([][]interface{}{
+		{"0", "MATCH", "orders:*", "COUNT", scanCount, "TYPE", "stream"},
+		{"17", "MATCH", "orders:*", "COUNT", scanCount, "TYPE", "stream"},
+	}, conn.args); diff != "" {
+		t.Errorf("Unexpected SCAN arguments (-want, +got): %v", diff)
+	}
+}
+
+// scanConn is a redis connection returning the canned SCAN replies in order.
+type scanConn struct {
+	redis.Conn
+	replies []interface{}
+	args    [][]interface{}
+}
+
+func (c *scanConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.args = append(c.args, args)
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
@@ -87,7 +87,7 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 			}
 
 			if len(item) != 2 {
-				return nil, fmt.Errorf("unexpected stream item slice length (%d)", len(elem))
+				return nil, fmt.Errorf("unexpected stream item slice length (%d)", len(item))
 			}
 
 			id, err := redis.String(item[0], nil)
@@ -96,6 +96,12 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 			}
 			dst[i].Items[j].ID = id
 
+			if item[1] == nil {
+				// The entry was deleted while pending, it has no field-value pairs.
+				dst[i].Items[j].FieldValues = nil
+				continue
+			}
+
 			fvs, err := redis.Values(item[1], nil)
 			if err != nil {
 				return nil, err
@@ -127,6 +133,10 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 //6) (integer) 2
 //7) last-delivered-id
 //8) "1588152489012-0"
+//9) entries-read
+//10) (integer) 4
+//11) lag
+//12) (integer) 1
 //1) 1) name
 //2) "some-other-group"
 //3) consumers
@@ -135,6 +145,12 @@ func ScanXReadReply(src []interface{}, dst StreamElements) (StreamElements, erro
 //6) (integer) 0
 //7) last-delivered-id
 //8) "1588152498034-0"
+//9) entries-read
+//10) (nil)
+//11) lag
+//12) (nil)
+//
+// The entries-read and lag fields are only returned since Redis 7.0.
 
 type StreamGroups map[string]StreamGroup
 
@@ -145,6 +161,9 @@ type StreamGroup struct {
 	Pending int
 	// LastDeliveredId is the ID of the last delivered item
 	LastDeliveredId string
+	// Lag is the number of entries of the stream not delivered to the group
+	// yet, nil when Redis cannot tell, for instance before Redis 7.0
+	Lag *int
 }
 
 func ScanXInfoGroupReply(reply interface{}, err error) (StreamGroups, error) {
@@ -167,30 +186,166 @@ func ScanXInfoGroupReply(reply interface{}, err error) (StreamGroups, error) {
 			return nil, fmt.Errorf("unexpected group reply size (%d)", len(entries))
 		}
 
-		name, err := redis.String(entries[1], nil)
-		if err != nil {
-			return nil, err
+		var name string
+		var info StreamGroup
+		for i := 0; i < len(entries); i += 2 {
+			field, err := redis.String(entries[i], nil)
+			if err != nil {
+				return nil, err
+			}
+
+			value := entries[i+1]
+			switch field {
+			case "name":
+				name, err = redis.String(value, nil)
+			case "consumers":
+				info.Consumers, err = redis.Int(value, nil)
+			case "pending":
+				info.Pending, err = redis.Int(value, nil)
+			case "last-delivered-id":
+				info.LastDeliveredId, err = redis.String(value, nil)
+			case "lag":
+				if value != nil {
+					var lag int
+					lag, err = redis.Int(value, nil)
+					info.Lag = &lag
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
 		}
+		dst[name] = info
+	}
+	return dst, nil
+}
 
-		consumers, err := redis.Int(entries[3], nil)
+//XINFO STREAM mystream
+// 1) "length"
+// 2) (integer) 2
+// 3) "radix-tree-keys"
+// 4) (integer) 1
+// 5) "radix-tree-nodes"
+// 6) (integer) 2
+// 7) "last-generated-id"
+// 8) "1638125141232-0"
+// 9) "groups"
+//10) (integer) 1
+//11) "first-entry"
+//12) 1) "1638125133432-0"
+//    2) 1) "message"
+//       2) "apple"
+//13) "last-entry"
+//14) 1) "1638125141232-0"
+//    2) 1) "message"
+//       2) "banana"
+//
+// Redis 7.0 and later return more fields, which are ignored.
+
+type StreamInfo struct {
+	// Length is the number of entries in the stream
+	Length int
+	// LastGeneratedID is the ID of the last entry added to the stream
+	LastGeneratedID string
+	// Groups is the number of consumer groups of the stream
+	Groups int
+}
+
+// ScanXInfoStreamReply scans the reply of XINFO STREAM.
+func ScanXInfoStreamReply(reply interface{}, err error) (StreamInfo, error) {
+	if err != nil {
+		return StreamInfo{}, err
+	}
+	entries, err := redis.Values(reply, nil)
+	if err != nil {
+		return StreamInfo{}, errors.New("expected a reply of type array")
+	}
+	if len(entries)%2 != 0 {
+		return StreamInfo{}, fmt.Errorf("unexpected stream reply size (%d)", len(entries))
+	}
+
+	var info StreamInfo
+	for i := 0; i < len(entries); i += 2 {
+		field, err := redis.String(entries[i], nil)
 		if err != nil {
-			return nil, err
+			return StreamInfo{}, err
 		}
 
-		pending, err := redis.Int(entries[5], nil)
+		value := entries[i+1]
+		switch field {
+		case "length":
+			info.Length, err = redis.Int(value, nil)
+		case "last-generated-id":
+			info.LastGeneratedID, err = redis.String(value, nil)
+		case "groups":
+			info.Groups, err = redis.Int(value, nil)
+		}
 		if err != nil {
-			return nil, err
+			return StreamInfo{}, err
 		}
+	}
+	return info, nil
+}
+
+//XINFO CONSUMERS mystream mygroup
+//1) 1) "name"
+//   2) "Alice"
+//   3) "pending"
+//   4) (integer) 1
+//   5) "idle"
+//   6) (integer) 9104628
+//
+// Redis 7.2 and later also return the inactive field, which is ignored.
 
-		lastid, err := redis.String(entries[7], nil)
+type StreamConsumers []StreamConsumer
+
+type StreamConsumer struct {
+	// Name is the consumer name
+	Name string
+	// Pending is the number of the pending messages of the consumer
+	Pending int
+	// Idle is the number of milliseconds since the consumer last interacted with the server
+	Idle int
+}
+
+// ScanXInfoConsumersReply scans the reply of XINFO CONSUMERS.
+func ScanXInfoConsumersReply(reply interface{}, err error) (StreamConsumers, error) {
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, errors.New("expected a reply of type array")
+	}
+	dst := make(StreamConsumers, len(consumers))
+
+	for i, consumer := range consumers {
+		entries, err := redis.Values(consumer, nil)
 		if err != nil {
 			return nil, err
 		}
+		if len(entries)%2 != 0 {
+			return nil, fmt.Errorf("unexpected consumer reply size (%d)", len(entries))
+		}
 
-		dst[name] = StreamGroup{
-			Consumers:       consumers,
-			Pending:         pending,
-			LastDeliveredId: lastid,
+		for j := 0; j < len(entries); j += 2 {
+			field, err := redis.String(entries[j], nil)
+			if err != nil {
+				return nil, err
+			}
+
+			value := entries[j+1]
+			switch field {
+			case "name":
+				dst[i].Name, err = redis.String(value, nil)
+			case "pending":
+				dst[i].Pending, err = redis.Int(value, nil)
+			case "idle":
+				dst[i].Idle, err = redis.Int(value, nil)
+			}
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	return dst, nil
@@ -268,3 +423,156 @@ func ScanXPendingReply(reply interface{}, err error) (PendingMessages, error) {
 	}
 	return dst, nil
 }
+
+//XAUTOCLAIM mystream mygroup Alice 3600000 0-0 COUNT 25
+//1) "0-0"
+//2) 1) 1) "1609338752495-0"
+//      2) 1) "field"
+//         2) "value"
+//3) (empty array)
+
+// ScanXAutoClaimReply scans the reply of XAUTOCLAIM and returns the cursor to
+// use in the next call along with the claimed items. Claimed items that no
+// longer exist in the stream are skipped.
+func ScanXAutoClaimReply(reply interface{}, err error) (string, []StreamItem, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return "", nil, errors.New("expected a reply of type array")
+	}
+
+	if len(values) != 2 && len(values) != 3 {
+		return "", nil, fmt.Errorf("unexpected autoclaim reply size (%d)", len(values))
+	}
+
+	cursor, err := redis.String(values[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	items, err := ScanXClaimReply(values[1], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return cursor, items, nil
+}
+
+//XAUTOCLAIM mystream mygroup Alice 3600000 0-0 COUNT 25 JUSTID
+//1) "0-0"
+//2) 1) "1609338752495-0"
+//3) (empty array)
+
+// ScanXAutoClaimJustIDReply scans the reply of XAUTOCLAIM with the JUSTID
+// option and returns the cursor to use in the next call along with the IDs
+// of the claimed items.
+func ScanXAutoClaimJustIDReply(reply interface{}, err error) (string, []string, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return "", nil, errors.New("expected a reply of type array")
+	}
+
+	if len(values) != 2 && len(values) != 3 {
+		return "", nil, fmt.Errorf("unexpected autoclaim reply size (%d)", len(values))
+	}
+
+	cursor, err := redis.String(values[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	ids, err := redis.Strings(values[1], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return cursor, ids, nil
+}
+
+//XCLAIM mystream mygroup Alice 3600000 1526569498055-0
+//1) 1) 1526569498055-0
+//   2) 1) "message"
+//      2) "orange"
+
+// ScanXClaimReply scans the reply of XCLAIM. Claimed items that no longer exist
+// in the stream are skipped.
+func ScanXClaimReply(reply interface{}, err error) ([]StreamItem, error) {
+	if err != nil {
+		return nil, err
+	}
+	rawitems, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, errors.New("expected a reply of type array")
+	}
+
+	items := make([]StreamItem, 0, len(rawitems))
+	for _, rawitem := range rawitems {
+		if rawitem == nil {
+			// deleted entry
+			continue
+		}
+		item, err := scanStreamItem(rawitem)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func scanStreamItem(rawitem interface{}) (StreamItem, error) {
+	item, err := redis.Values(rawitem, nil)
+	if err != nil {
+		return StreamItem{}, err
+	}
+
+	if len(item) != 2 {
+		return StreamItem{}, fmt.Errorf("unexpected stream item slice length (%d)", len(item))
+	}
+
+	id, err := redis.String(item[0], nil)
+	if err != nil {
+		return StreamItem{}, err
+	}
+
+	fvs, err := redis.Strings(item[1], nil)
+	if err != nil {
+		return StreamItem{}, err
+	}
+	return StreamItem{ID: id, FieldValues: fvs}, nil
+}
+
+//SCAN 0 MATCH orders:* COUNT 100 TYPE stream
+//1) "17"
+//2) 1) "orders:{acme}"
+//   2) "orders:{globex}"
+
+// ScanScanReply scans the reply of SCAN and returns the cursor to use in the
+// next call along with the keys.
+func ScanScanReply(reply interface{}, err error) (string, []string, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := redis.Values(reply, nil)
+	if err != nil {
+		return "", nil, errors.New("expected a reply of type array")
+	}
+
+	if len(values) != 2 {
+		return "", nil, fmt.Errorf("unexpected scan reply size (%d)", len(values))
+	}
+
+	cursor, err := redis.String(values[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keys, err := redis.Strings(values[1], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return cursor, keys, nil
+}
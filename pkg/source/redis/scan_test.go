@@ -59,6 +59,55 @@ func TestScanXRead(t *testing.T) {
 				},
 			},
 		},
+		{
+			reply: []interface{}{
+				[]interface{}{
+					[]byte("tenant-a"),
+					[]interface{}{
+						[]interface{}{
+							[]byte("1519073278252-0"),
+							[]interface{}{
+								[]byte("foo"),
+								[]byte("value_1")}},
+						[]interface{}{
+							[]byte("1519073278253-0"),
+							nil}}},
+				[]interface{}{
+					[]byte("tenant-b"),
+					[]interface{}{
+						[]interface{}{
+							[]byte("1519073279157-0"),
+							[]interface{}{
+								[]byte("foo"),
+								[]byte("value_2")}}}}},
+			expected: []StreamElement{
+				{
+					Name: "tenant-a",
+					Items: []StreamItem{
+						{
+							ID: "1519073278252-0",
+							FieldValues: []string{
+								"foo",
+								"value_1"},
+						},
+						{
+							ID: "1519073278253-0",
+						},
+					},
+				},
+				{
+					Name: "tenant-b",
+					Items: []StreamItem{
+						{
+							ID: "1519073279157-0",
+							FieldValues: []string{
+								"foo",
+								"value_2"},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tests {
 		actual, err := ScanXReadReply(tc.reply, nil)
@@ -73,3 +122,176 @@ func TestScanXRead(t *testing.T) {
 	}
 
 }
+
+func TestScanXAutoClaim(t *testing.T) {
+	reply := []interface{}{
+		[]byte("1519073279157-0"),
+		[]interface{}{
+			[]interface{}{
+				[]byte("1519073278252-0"),
+				[]interface{}{
+					[]byte("foo"),
+					[]byte("value_1")}},
+			nil},
+		[]interface{}{}}
+
+	cursor, items, err := ScanXAutoClaimReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if cursor != "1519073279157-0" {
+		t.Errorf("Unexpected cursor: %s", cursor)
+	}
+
+	expected := []StreamItem{{
+		ID:          "1519073278252-0",
+		FieldValues: []string{"foo", "value_1"},
+	}}
+	if diff := cmp.Diff(expected, items); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanXAutoClaimJustID(t *testing.T) {
+	reply := []interface{}{
+		[]byte("0-0"),
+		[]interface{}{[]byte("1519073278252-0"), []byte("1519073279157-0")},
+		[]interface{}{}}
+
+	cursor, ids, err := ScanXAutoClaimJustIDReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if cursor != "0-0" {
+		t.Errorf("Unexpected cursor: %s", cursor)
+	}
+	if diff := cmp.Diff([]string{"1519073278252-0", "1519073279157-0"}, ids); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanXClaim(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("1526569498055-0"),
+			[]interface{}{
+				[]byte("message"),
+				[]byte("orange")}}}
+
+	items, err := ScanXClaimReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := []StreamItem{{
+		ID:          "1526569498055-0",
+		FieldValues: []string{"message", "orange"},
+	}}
+	if diff := cmp.Diff(expected, items); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanScan(t *testing.T) {
+	reply := []interface{}{
+		[]byte("17"),
+		[]interface{}{
+			[]byte("orders:{acme}"),
+			[]byte("orders:{globex}")}}
+
+	cursor, keys, err := ScanScanReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if cursor != "17" {
+		t.Errorf("Unexpected cursor: %v", cursor)
+	}
+	if diff := cmp.Diff([]string{"orders:{acme}", "orders:{globex}"}, keys); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanXInfoGroup(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("name"), []byte("mygroup"),
+			[]byte("consumers"), int64(2),
+			[]byte("pending"), int64(2),
+			[]byte("last-delivered-id"), []byte("1588152489012-0"),
+			[]byte("entries-read"), int64(4),
+			[]byte("lag"), int64(1)},
+		[]interface{}{
+			[]byte("name"), []byte("some-other-group"),
+			[]byte("consumers"), int64(1),
+			[]byte("pending"), int64(0),
+			[]byte("last-delivered-id"), []byte("1588152498034-0"),
+			[]byte("entries-read"), nil,
+			[]byte("lag"), nil},
+		[]interface{}{
+			[]byte("name"), []byte("redis-6-group"),
+			[]byte("consumers"), int64(0),
+			[]byte("pending"), int64(0),
+			[]byte("last-delivered-id"), []byte("0-0")}}
+
+	groups, err := ScanXInfoGroupReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	lag := 1
+	want := StreamGroups{
+		"mygroup":          {Consumers: 2, Pending: 2, LastDeliveredId: "1588152489012-0", Lag: &lag},
+		"some-other-group": {Consumers: 1, Pending: 0, LastDeliveredId: "1588152498034-0"},
+		"redis-6-group":    {LastDeliveredId: "0-0"},
+	}
+	if diff := cmp.Diff(want, groups); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanXInfoStream(t *testing.T) {
+	reply := []interface{}{
+		[]byte("length"), int64(2),
+		[]byte("radix-tree-keys"), int64(1),
+		[]byte("radix-tree-nodes"), int64(2),
+		[]byte("last-generated-id"), []byte("1638125141232-0"),
+		[]byte("groups"), int64(1),
+		[]byte("first-entry"), []interface{}{[]byte("1638125133432-0"), []interface{}{[]byte("message"), []byte("apple")}},
+		[]byte("last-entry"), []interface{}{[]byte("1638125141232-0"), []interface{}{[]byte("message"), []byte("banana")}}}
+
+	info, err := ScanXInfoStreamReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	want := StreamInfo{Length: 2, LastGeneratedID: "1638125141232-0", Groups: 1}
+	if diff := cmp.Diff(want, info); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
+
+func TestScanXInfoConsumers(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("name"), []byte("Alice"),
+			[]byte("pending"), int64(1),
+			[]byte("idle"), int64(9104628)},
+		[]interface{}{
+			[]byte("name"), []byte("Bob"),
+			[]byte("pending"), int64(0),
+			[]byte("idle"), int64(83841983),
+			[]byte("inactive"), int64(-1)}}
+
+	consumers, err := ScanXInfoConsumersReply(reply, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	want := StreamConsumers{
+		{Name: "Alice", Pending: 1, Idle: 9104628},
+		{Name: "Bob", Idle: 83841983},
+	}
+	if diff := cmp.Diff(want, consumers); diff != "" {
+		t.Errorf("Unexpected difference (-want, +got): %v", diff)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+)
+
+var entryIDPattern = regexp.MustCompile(`^[0-9]+(-[0-9]+)?$`)
+
+// StartID converts the start position of a consumer group to the ID passed to
+// XGROUP CREATE or XGROUP SETID. The position is either "0" for the beginning
+// of the stream, "$" for new entries only, an entry ID, or an RFC 3339
+// timestamp. An empty position defaults to "$".
+func StartID(startFrom string) (string, error) {
+	switch {
+	case startFrom == "":
+		return "$", nil
+	case startFrom == "$" || entryIDPattern.MatchString(startFrom):
+		return startFrom, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, startFrom)
+	if err != nil {
+		return "", fmt.Errorf("invalid start position %q, expected 0, $, an entry ID or an RFC 3339 timestamp", startFrom)
+	}
+	// Entry IDs start with their creation time in milliseconds. The ID is the
+	// last delivered one, the greatest ID of the previous millisecond, so that
+	// the entries added at the timestamp are read.
+	ms := t.UnixNano() / int64(time.Millisecond)
+	if ms <= 0 {
+		return "0", nil
+	}
+	return fmt.Sprintf("%d-%d", ms-1, uint64(math.MaxUint64)), nil
+}
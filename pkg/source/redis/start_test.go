@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scan
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStartID(t *testing.T) {
+	tests := map[string]struct {
+		startFrom string
+		want      string
+		wantErr   bool
+	}{
+		"default":        {startFrom: "", want: "$"},
+		"new entries":    {startFrom: "$", want: "$"},
+		"beginning":      {startFrom: "0", want: "0"},
+		"entry ID":       {startFrom: "1526919030474-55", want: "1526919030474-55"},
+		"entry ms":       {startFrom: "1526919030474", want: "1526919030474"},
+		"timestamp":      {startFrom: "2018-05-21T16:10:30.474Z", want: "1526919030473-18446744073709551615"},
+		"timestamp zone": {startFrom: "2018-05-21T18:10:30+02:00", want: "1526919029999-18446744073709551615"},
+		"epoch":          {startFrom: "1970-01-01T00:00:00Z", want: "0"},
+		"invalid":        {startFrom: "yesterday", wantErr: true},
+		"invalid ID":     {startFrom: "1526919030474-", wantErr: true},
+	}
+
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got, err := StartID(tc.startFrom)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestStartIDBoundary checks that the entries added at the timestamp are
+// read, and the ones added before are not, XGROUP treating the ID as the
+// last delivered one.
+func TestStartIDBoundary(t *testing.T) {
+	startID, err := StartID("2018-05-21T16:10:30.474Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var startMs, startSeq uint64
+	if _, err := fmt.Sscanf(startID, "%d-%d", &startMs, &startSeq); err != nil {
+		t.Fatalf("unexpected ID %q: %v", startID, err)
+	}
+	after := func(ms, seq uint64) bool {
+		return ms > startMs || (ms == startMs && seq > startSeq)
+	}
+
+	if !after(1526919030474, 0) {
+		t.Error("the first entry added at the timestamp is skipped")
+	}
+	if after(1526919030473, 99) {
+		t.Error("an entry added before the timestamp is read")
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook runs the defaulting and validating admission webhooks of
+// the Redis resources.
+package webhook
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/certificates"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+)
+
+// Main runs the webhooks defaulting and validating types until the process
+// is signaled. The webhooks are named after group, for instance
+// redis.sources.knative.dev.
+func Main(group string, types map[schema.GroupVersionKind]resourcesemantics.GenericCRD) {
+	ctx := webhook.WithOptions(signals.NewContext(), webhook.Options{
+		ServiceName: webhook.NameFromEnv(),
+		Port:        webhook.PortFromEnv(8443),
+		SecretName:  webhook.SecretNameFromEnv("redis-webhook-certs"),
+	})
+
+	sharedmain.MainWithContext(ctx, webhook.NameFromEnv(),
+		certificates.NewController,
+		NewDefaultingAdmissionController(group, types),
+		NewValidationAdmissionController(group, types),
+	)
+}
+
+// NewDefaultingAdmissionController returns the constructor of the webhook
+// defaulting types.
+func NewDefaultingAdmissionController(group string, types map[schema.GroupVersionKind]resourcesemantics.GenericCRD) injection.ControllerConstructor {
+	return func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+		return defaulting.NewAdmissionController(ctx,
+			// Name of the resource webhook.
+			"defaulting.webhook."+group,
+
+			// The path on which to serve the webhook.
+			"/defaulting",
+
+			// The resources to default.
+			types,
+
+			// A function that infuses the context passed to SetDefaults with custom metadata.
+			func(ctx context.Context) context.Context { return ctx },
+
+			// Whether to disallow unknown fields.
+			true,
+		)
+	}
+}
+
+// NewValidationAdmissionController returns the constructor of the webhook
+// validating types.
+func NewValidationAdmissionController(group string, types map[schema.GroupVersionKind]resourcesemantics.GenericCRD) injection.ControllerConstructor {
+	return func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+		return validation.NewAdmissionController(ctx,
+			// Name of the resource webhook.
+			"validation.webhook."+group,
+
+			// The path on which to serve the webhook.
+			"/resource-validation",
+
+			// The resources to validate.
+			types,
+
+			// A function that infuses the context passed to Validate with custom metadata.
+			func(ctx context.Context) context.Context { return ctx },
+
+			// Whether to disallow unknown fields.
+			true,
+		)
+	}
+}